@@ -0,0 +1,89 @@
+package ffclient
+
+import (
+	"github.com/thomaspoignant/go-feature-flag/ffuser"
+	"github.com/thomaspoignant/go-feature-flag/internal/model"
+)
+
+// AllFlags is the evaluation of all the flags for a user, it can be serialized in JSON
+// to bootstrap a frontend application.
+type AllFlags struct {
+	// Flags contains the evaluation of each flag, the key is the name of the flag.
+	Flags map[string]FlagState `json:"flags"`
+
+	// Valid is false if we were not able to read the flags (ex: go-feature-flag not initialised).
+	Valid bool `json:"valid"`
+}
+
+// FlagState is the evaluation of one flag for a user.
+type FlagState struct {
+	Value       interface{}      `json:"value"`
+	Variation   string           `json:"variation"`
+	Reason      ResolutionReason `json:"reason"`
+	RuleName    string           `json:"ruleName,omitempty"`
+	TrackEvents bool             `json:"trackEvents"`
+
+	// ClientSide is not serialized, it is only used to filter the flags with ClientSideOnly.
+	ClientSide bool `json:"-"`
+}
+
+// ClientSideOnly returns a copy of AllFlags containing only the flags with the clientSide attribute
+// set to true, use it before sending the flags to a client side application.
+func (a AllFlags) ClientSideOnly() AllFlags {
+	res := AllFlags{
+		Flags: make(map[string]FlagState),
+		Valid: a.Valid,
+	}
+	for key, state := range a.Flags {
+		if state.ClientSide {
+			res.Flags[key] = state
+		}
+	}
+	return res
+}
+
+// AllFlagsState return the evaluation of all the flags for a user.
+// An invalid AllFlags is return if you don't have init the library before calling the function.
+// Note: No event is sent to the data exporter for these evaluations.
+func AllFlagsState(user ffuser.User) AllFlags {
+	return ff.AllFlagsState(user)
+}
+
+// AllFlagsState return the evaluation of all the flags for a user.
+// An invalid AllFlags is return if you don't have init the library before calling the function.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+// No event is sent to the data exporter for these evaluations.
+func (g *GoFeatureFlag) AllFlagsState(user ffuser.User) AllFlags {
+	if g.remote != nil {
+		return g.remote.allFlagsState(user)
+	}
+
+	// the prerequisites and segments are read from the snapshot, not from the cache that can be updated
+	// during the evaluation of the flags.
+	snapshot, err := g.cache.Snapshot()
+	if err != nil {
+		return AllFlags{Flags: make(map[string]FlagState), Valid: false}
+	}
+
+	res := AllFlags{
+		Flags: make(map[string]FlagState, len(snapshot.Flags)),
+		Valid: true,
+	}
+	for key, flag := range snapshot.Flags {
+		res.Flags[key] = evaluateFlagState(key, flag, user, snapshot)
+	}
+	return res
+}
+
+// evaluateFlagState evaluates one flag of the cache for the user.
+func evaluateFlagState(flagKey string, flag model.FlagData, user ffuser.User, flagSet model.FlagSet) FlagState {
+	value, resolution := flag.Evaluate(flagKey, user, flagSet)
+	return FlagState{
+		Value:       value,
+		Variation:   string(resolution.Variation),
+		Reason:      resolution.Reason,
+		RuleName:    resolution.RuleName,
+		TrackEvents: flag.GetTrackEvents(),
+		ClientSide:  flag.GetClientSide(),
+	}
+}
@@ -0,0 +1,166 @@
+package ffclient
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thomaspoignant/go-feature-flag/ffuser"
+	"github.com/thomaspoignant/go-feature-flag/internal/cache"
+	"github.com/thomaspoignant/go-feature-flag/internal/notifier"
+)
+
+func TestAllFlagsState(t *testing.T) {
+	flagCache := cache.New(cache.NewNotificationService([]notifier.Notifier{}))
+	err := flagCache.UpdateCache([]byte(`test-flag:
+  rule: key eq "random-key"
+  percentage: 100
+  true: true
+  false: false
+  default: false
+  clientSide: true
+
+color:
+  variations:
+    blue: "#0000FF"
+    green: "#00FF00"
+  targeting:
+    - name: beta
+      query: key eq "random-key"
+      variation: green
+  default: "#FFFFFF"
+  trackEvents: false
+
+disable-flag:
+  true: true
+  false: false
+  default: false
+  disable: true
+  clientSide: true
+`), "yaml")
+	assert.NoError(t, err)
+	defer flagCache.Close()
+
+	gffClient := &GoFeatureFlag{cache: flagCache}
+	got := gffClient.AllFlagsState(ffuser.NewUser("random-key"))
+
+	want := AllFlags{
+		Valid: true,
+		Flags: map[string]FlagState{
+			"test-flag": {
+				Value:       true,
+				Variation:   "True",
+				Reason:      ReasonTargetingMatch,
+				TrackEvents: true,
+				ClientSide:  true,
+			},
+			"color": {
+				Value:     "#00FF00",
+				Variation: "green",
+				Reason:    ReasonTargetingMatch,
+				RuleName:  "beta",
+			},
+			"disable-flag": {
+				Value:       false,
+				Variation:   "Default",
+				Reason:      ReasonDisabled,
+				TrackEvents: true,
+				ClientSide:  true,
+			},
+		},
+	}
+	assert.Equal(t, want, got)
+
+	clientSide := got.ClientSideOnly()
+	assert.True(t, clientSide.Valid)
+	assert.Len(t, clientSide.Flags, 2)
+	assert.Contains(t, clientSide.Flags, "test-flag")
+	assert.Contains(t, clientSide.Flags, "disable-flag")
+	assert.NotContains(t, clientSide.Flags, "color")
+
+	serialized, err := json.Marshal(clientSide)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+  "flags": {
+    "test-flag": {"value": true, "variation": "True", "reason": "TARGETING_MATCH", "trackEvents": true},
+    "disable-flag": {"value": false, "variation": "Default", "reason": "DISABLED", "trackEvents": true}
+  },
+  "valid": true
+}`, string(serialized))
+}
+
+func TestAllFlagsState_notInit(t *testing.T) {
+	flagCache := cache.New(cache.NewNotificationService([]notifier.Notifier{}))
+	flagCache.Close()
+
+	gffClient := &GoFeatureFlag{cache: flagCache}
+	got := gffClient.AllFlagsState(ffuser.NewUser("random-key"))
+	assert.False(t, got.Valid)
+	assert.Empty(t, got.Flags)
+}
+
+// updatedCache updates the flags of the cache just after they are copied, like a refresh of the flags
+// during the evaluation of all the flags.
+type updatedCache struct {
+	cache.Cache
+	newFlags []byte
+}
+
+func (c *updatedCache) AllFlags() (cache.FlagsCache, error) {
+	flags, err := c.Cache.AllFlags()
+	if err != nil {
+		return flags, err
+	}
+	return flags, c.Cache.UpdateCache(c.newFlags, "yaml")
+}
+
+func (c *updatedCache) Snapshot() (cache.Snapshot, error) {
+	snapshot, err := c.Cache.Snapshot()
+	if err != nil {
+		return snapshot, err
+	}
+	return snapshot, c.Cache.UpdateCache(c.newFlags, "yaml")
+}
+
+func TestAllFlagsState_sameVersion(t *testing.T) {
+	flagCache := cache.New(cache.NewNotificationService([]notifier.Notifier{}))
+	defer flagCache.Close()
+	err := flagCache.UpdateCache([]byte(`prerequisite:
+  percentage: 100
+  true: true
+  false: false
+  default: false
+
+test-flag:
+  prerequisites:
+    - key: prerequisite
+      variation: "True"
+  percentage: 100
+  true: true
+  false: false
+  default: false
+`), "yaml")
+	assert.NoError(t, err)
+
+	gffClient := &GoFeatureFlag{cache: &updatedCache{Cache: flagCache, newFlags: []byte(`prerequisite:
+  percentage: 0
+  true: true
+  false: false
+  default: false
+
+test-flag:
+  prerequisites:
+    - key: prerequisite
+      variation: "True"
+  percentage: 100
+  true: true
+  false: false
+  default: false
+`)}}
+	got := gffClient.AllFlagsState(ffuser.NewUser("random-key"))
+
+	// the prerequisite is evaluated with the flags of the snapshot, not with the new flags of the cache.
+	assert.Equal(t, true, got.Flags["prerequisite"].Value)
+	assert.Equal(t, true, got.Flags["test-flag"].Value)
+}
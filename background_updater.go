@@ -9,6 +9,9 @@ import (
 type backgroundUpdater struct {
 	ticker      *time.Ticker
 	updaterChan chan struct{}
+
+	// triggerChan is used to ask for an update of the flags without waiting for the ticker.
+	triggerChan chan struct{}
 }
 
 // newBackgroundUpdater init default value for the ticker and the channel.
@@ -16,11 +19,25 @@ func newBackgroundUpdater(pollingInterval time.Duration) backgroundUpdater {
 	return backgroundUpdater{
 		ticker:      time.NewTicker(pollingInterval),
 		updaterChan: make(chan struct{}),
+		triggerChan: make(chan struct{}, 1),
+	}
+}
+
+// trigger asks for an update of the flags as soon as possible.
+// If an update is already waiting, there is no need to ask for another one.
+func (bgu *backgroundUpdater) trigger() {
+	select {
+	case bgu.triggerChan <- struct{}{}:
+	default:
 	}
 }
 
 // close stop the ticker and close the channel.
 func (bgu *backgroundUpdater) close() {
+	// there is no background update when the flags are evaluated by a relay proxy.
+	if bgu.ticker == nil {
+		return
+	}
 	bgu.ticker.Stop()
 	close(bgu.updaterChan)
 }
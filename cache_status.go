@@ -0,0 +1,43 @@
+package ffclient
+
+import "time"
+
+// CacheStatus describes the flags currently used by go-feature-flag,
+// use it to know how old your flags are.
+type CacheStatus struct {
+	// LastUpdate is the date of the last successful retrieval of the flags,
+	// it is zero if the flags have never been retrieved.
+	LastUpdate time.Time
+
+	// FromPersistentFile is true if the flags were loaded from the persistent flag file
+	// because the retriever was failing at startup.
+	FromPersistentFile bool
+}
+
+// Age returns how old the flags are, 0 if the flags have never been retrieved.
+func (c CacheStatus) Age() time.Duration {
+	if c.LastUpdate.IsZero() {
+		return 0
+	}
+	return time.Since(c.LastUpdate)
+}
+
+// GetCacheStatus returns the status of the flags currently used.
+func GetCacheStatus() CacheStatus {
+	return ff.GetCacheStatus()
+}
+
+// GetCacheStatus returns the status of the flags currently used.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) GetCacheStatus() CacheStatus {
+	g.cacheStatusMutex.RLock()
+	defer g.cacheStatusMutex.RUnlock()
+	return g.cacheStatus
+}
+
+// setCacheStatus is called every time the flags are updated.
+func (g *GoFeatureFlag) setCacheStatus(status CacheStatus) {
+	g.cacheStatusMutex.Lock()
+	defer g.cacheStatusMutex.Unlock()
+	g.cacheStatus = status
+}
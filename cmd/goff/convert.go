@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/thomaspoignant/go-feature-flag/internal/cache"
+)
+
+// runConvert converts a flag file in another format and writes it in the output file or in out.
+func runConvert(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	format := flags.String("format", "", "format of the input file (yaml, json or toml)")
+	to := flags.String("to", "", "format of the converted file (yaml, json or toml)")
+	output := flags.String("output", "", "file where to write the converted file, default is the standard output")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidUsage, err)
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("%w: convert needs one file", errInvalidUsage)
+	}
+	if *to == "" {
+		if *output == "" {
+			return fmt.Errorf("%w: convert needs --to or --output", errInvalidUsage)
+		}
+		*to = fileFormat(*output, "")
+	}
+
+	path := flags.Arg(0)
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	converted, err := cache.ConvertFlagFile(content, fileFormat(path, *format), *to)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = out.Write(converted)
+		return err
+	}
+	return ioutil.WriteFile(*output, converted, os.FileMode(0644))
+}
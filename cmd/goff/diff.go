@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sort"
+
+	"github.com/thomaspoignant/go-feature-flag/internal/cache"
+	"github.com/thomaspoignant/go-feature-flag/internal/model"
+)
+
+// runDiff prints the differences between two flag files.
+func runDiff(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	format := flags.String("format", "", "format of the files (yaml, json or toml)")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidUsage, err)
+	}
+	if flags.NArg() != 2 {
+		return fmt.Errorf("%w: diff needs two files", errInvalidUsage)
+	}
+
+	oldFlags, oldSegments, err := readFlagFile(flags.Arg(0), *format)
+	if err != nil {
+		return fmt.Errorf("%s: %v", flags.Arg(0), err)
+	}
+	newFlags, newSegments, err := readFlagFile(flags.Arg(1), *format)
+	if err != nil {
+		return fmt.Errorf("%s: %v", flags.Arg(1), err)
+	}
+
+	// the flags are compared with their JSON values, the formats don't read the numbers with the same types.
+	if oldFlags, err = normalizeFlags(oldFlags); err != nil {
+		return fmt.Errorf("%s: %v", flags.Arg(0), err)
+	}
+	if newFlags, err = normalizeFlags(newFlags); err != nil {
+		return fmt.Errorf("%s: %v", flags.Arg(1), err)
+	}
+
+	diff := cache.Diff(oldFlags, newFlags, oldSegments, newSegments)
+	if !diff.HasDiff() {
+		fmt.Fprintln(out, "no difference")
+		return nil
+	}
+	printDiff(out, diff)
+	return nil
+}
+
+// normalizeFlags converts the values of the flags in the types of JSON (ex: the integers of YAML and TOML
+// are float64), so the same flag written in two formats has no difference.
+func normalizeFlags(flags cache.FlagsCache) (cache.FlagsCache, error) {
+	content, err := json.Marshal(flags)
+	if err != nil {
+		return nil, err
+	}
+	var normalized cache.FlagsCache
+	if err := json.Unmarshal(content, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// printDiff prints the differences sorted by key, one block per flag or segment.
+func printDiff(out io.Writer, diff model.DiffCache) {
+	for _, key := range sortedKeys(diff.Deleted) {
+		fmt.Fprintf(out, "- flag %s removed\n", key)
+	}
+	for _, key := range sortedKeys(diff.Added) {
+		fmt.Fprintf(out, "+ flag %s added\n    %v\n", key, diff.Added[key])
+	}
+	for _, key := range sortedKeys(diff.Updated) {
+		fmt.Fprintf(out, "~ flag %s updated\n    - %v\n    + %v\n", key, diff.Updated[key].Before, diff.Updated[key].After)
+	}
+
+	if diff.Segments == nil {
+		return
+	}
+	for _, key := range sortedKeys(diff.Segments.Deleted) {
+		fmt.Fprintf(out, "- segment %s removed\n", key)
+	}
+	for _, key := range sortedKeys(diff.Segments.Added) {
+		fmt.Fprintf(out, "+ segment %s added\n    %v\n", key, diff.Segments.Added[key])
+	}
+	for _, key := range sortedKeys(diff.Segments.Updated) {
+		segmentDiff := diff.Segments.Updated[key]
+		fmt.Fprintf(out, "~ segment %s updated\n    - %v\n    + %v\n", key, segmentDiff.Before, segmentDiff.After)
+	}
+}
+
+// sortedKeys returns the keys of a map with string keys, sorted.
+func sortedKeys(m interface{}) []string {
+	values := reflect.ValueOf(m).MapKeys()
+	keys := make([]string, 0, len(values))
+	for _, value := range values {
+		keys = append(keys, value.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
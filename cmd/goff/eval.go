@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+	"github.com/thomaspoignant/go-feature-flag/ffuser"
+)
+
+// attributes is a flag.Value to collect the --attr name=value flags.
+type attributes map[string]interface{}
+
+func (a attributes) String() string {
+	return fmt.Sprintf("%v", map[string]interface{}(a))
+}
+
+// Set parses name=value, the value is read as JSON if possible (ex: true, 12) else as a string.
+func (a attributes) Set(s string) error {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return fmt.Errorf("attribute %q should be name=value", s)
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(parts[1]), &value); err != nil {
+		value = parts[1]
+	}
+	a[parts[0]] = value
+	return nil
+}
+
+// runEval evaluates a flag for a user like the library does and prints the result in JSON.
+// A disabled flag serves the --default value with the reason DISABLED.
+func runEval(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("eval", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	format := flags.String("format", "", "format of the file (yaml, json or toml)")
+	flagName := flags.String("flag", "", "name of the flag to evaluate")
+	userJSON := flags.String("user", "", `user in JSON (ex: {"key": "user-key", "custom": {"beta": true}})`)
+	key := flags.String("key", "", "key of the user")
+	anonymous := flags.Bool("anonymous", false, "true if the user is anonymous")
+	defaultValue := flags.String("default", "null", "default value served if the flag is disabled, in JSON")
+	attrs := attributes{}
+	flags.Var(attrs, "attr", "custom attribute of the user name=value, can be repeated")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidUsage, err)
+	}
+	if flags.NArg() != 1 || *flagName == "" {
+		return fmt.Errorf("%w: eval needs a --flag and one file", errInvalidUsage)
+	}
+
+	user, err := buildUser(*userJSON, *key, *anonymous, attrs)
+	if err != nil {
+		return err
+	}
+
+	var sdkDefault interface{}
+	if err := json.Unmarshal([]byte(*defaultValue), &sdkDefault); err != nil {
+		return fmt.Errorf("%w: invalid --default: %v", errInvalidUsage, err)
+	}
+
+	// the file is validated first to return the same errors as lint.
+	path := flags.Arg(0)
+	if _, _, err := readFlagFile(path, *format); err != nil {
+		return err
+	}
+	goff, err := ffclient.New(ffclient.Config{
+		Retriever: &ffclient.FileRetriever{Path: path, FileFormat: fileFormat(path, *format)},
+	})
+	if err != nil {
+		return err
+	}
+	defer goff.Close()
+
+	details, err := goff.RawVariationDetails(*flagName, user, sdkDefault)
+	if err != nil && details.ErrorCode != "" {
+		return err
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(details)
+}
+
+// buildUser creates the user from the --user flag or from the --key, --anonymous and --attr flags.
+func buildUser(userJSON string, key string, anonymous bool, attrs attributes) (ffuser.User, error) {
+	if userJSON != "" {
+		if key != "" || len(attrs) > 0 {
+			return ffuser.User{}, fmt.Errorf("%w: --user cannot be used with --key or --attr", errInvalidUsage)
+		}
+		var user ffuser.User
+		if err := json.Unmarshal([]byte(userJSON), &user); err != nil {
+			return ffuser.User{}, fmt.Errorf("%w: invalid --user: %v", errInvalidUsage, err)
+		}
+		if user.GetKey() == "" {
+			return ffuser.User{}, fmt.Errorf("%w: the user needs a key", errInvalidUsage)
+		}
+		return user, nil
+	}
+	if key == "" {
+		return ffuser.User{}, fmt.Errorf("%w: the user needs a key", errInvalidUsage)
+	}
+
+	builder := ffuser.NewUserBuilder(key).Anonymous(anonymous)
+	for name, value := range attrs {
+		builder = builder.AddCustom(name, value)
+	}
+	return builder.Build(), nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/thomaspoignant/go-feature-flag/internal/cache"
+)
+
+// runLint checks all the files and prints every problem found.
+func runLint(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	format := flags.String("format", "", "format of the files (yaml, json or toml)")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidUsage, err)
+	}
+	if flags.NArg() == 0 {
+		return fmt.Errorf("%w: lint needs at least one file", errInvalidUsage)
+	}
+
+	invalidFiles := 0
+	for _, path := range flags.Args() {
+		_, _, err := readFlagFile(path, *format)
+		if err == nil {
+			fmt.Fprintf(out, "%s: OK\n", path)
+			continue
+		}
+
+		invalidFiles++
+		var validationErr *cache.ValidationError
+		if errors.As(err, &validationErr) {
+			for _, e := range validationErr.Errors {
+				fmt.Fprintf(out, "%s: %v\n", path, e)
+			}
+			continue
+		}
+		fmt.Fprintf(out, "%s: %v\n", path, err)
+	}
+
+	if invalidFiles > 0 {
+		return fmt.Errorf("%d invalid file(s)", invalidFiles)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// goff is a command line tool to work with go-feature-flag flag files.
+//
+// Usage:
+//
+//	goff lint [--format yaml|json|toml] <file>...
+//	goff eval [--format yaml|json|toml] --flag <flag> [--default <json>] --user <json> <file>
+//	goff eval [--format yaml|json|toml] --flag <flag> [--default <json>] --key <key> [--anonymous]
+//	          [--attr name=value]... <file>
+//	goff diff [--format yaml|json|toml] <old file> <new file>
+//	goff convert [--format yaml|json|toml] [--to yaml|json|toml] [--output <file>] <file>
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/thomaspoignant/go-feature-flag/internal/cache"
+)
+
+const usage = `goff is a tool to work with go-feature-flag flag files.
+
+Usage:
+  goff lint [--format yaml|json|toml] <file>...
+  goff eval [--format yaml|json|toml] --flag <flag> [--default <json>] --user <json> <file>
+  goff eval [--format yaml|json|toml] --flag <flag> [--default <json>] --key <key> [--anonymous]
+            [--attr name=value]... <file>
+  goff diff [--format yaml|json|toml] <old file> <new file>
+  goff convert [--format yaml|json|toml] [--to yaml|json|toml] [--output <file>] <file>
+
+Commands:
+  lint      check that the flag files are valid
+  eval      evaluate a flag for a user like the library and print the value, the variation and the reason
+  diff      print the differences between two flag files
+  convert   convert a flag file to another format, the format of --output is used if --to is not set
+
+The format of the files is detected with their extension if --format is not set.
+`
+
+// errInvalidUsage is returned when the arguments of a command are invalid.
+var errInvalidUsage = errors.New("invalid usage")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command and returns the exit code.
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	var err error
+	switch args[0] {
+	case "lint":
+		err = runLint(args[1:], stdout)
+	case "eval":
+		err = runEval(args[1:], stdout)
+	case "diff":
+		err = runDiff(args[1:], stdout)
+	case "convert":
+		err = runConvert(args[1:], stdout)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		err = fmt.Errorf("%w: unknown command %q", errInvalidUsage, args[0])
+	}
+
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		if errors.Is(err, errInvalidUsage) {
+			fmt.Fprint(stderr, "\n"+usage)
+			return 2
+		}
+		return 1
+	}
+	return 0
+}
+
+// readFlagFile reads and validates a flag file.
+func readFlagFile(path string, format string) (cache.FlagsCache, cache.SegmentsCache, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cache.ParseFlagFile(content, fileFormat(path, format))
+}
+
+// fileFormat returns the format of the file, if no format is set we use the extension of the file.
+func fileFormat(path string, format string) string {
+	if format != "" {
+		return format
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return "json"
+	case ".toml":
+		return "toml"
+	default:
+		return "yaml"
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, dir string, name string, content string) string {
+	path := filepath.Join(dir, name)
+	assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goff")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	validFile := "../../testdata/flag-config.yaml"
+	invalidFile := writeFile(t, dir, "invalid.yaml", `test-flag:
+  rule: key eq
+  percentage: 120
+  true: true
+  false: false
+  default: false
+`)
+	newFile := writeFile(t, dir, "new.json", `{
+  "test-flag": {"rule": "key eq \"random-key\"", "percentage": 50, "true": true, "false": false, "default": false},
+  "new-flag": {"true": "A", "false": "B", "default": "C"}
+}`)
+	disabledFile := writeFile(t, dir, "disabled.yaml", "disabled-flag:\n  true: true\n  false: false\n  default: false\n"+
+		"  disable: true\n")
+	intFileYAML := writeFile(t, dir, "int.yaml", "int-flag:\n  true: 3\n  false: 1\n  default: 0\n")
+	intFileTOML := writeFile(t, dir, "int.toml", "[int-flag]\ntrue = 3\nfalse = 1\ndefault = 0\n")
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:       "No command",
+			args:       []string{},
+			wantCode:   2,
+			wantStderr: "Usage:",
+		},
+		{
+			name:       "Unknown command",
+			args:       []string{"unknown"},
+			wantCode:   2,
+			wantStderr: `unknown command "unknown"`,
+		},
+		{
+			name:       "Lint valid file",
+			args:       []string{"lint", validFile},
+			wantCode:   0,
+			wantStdout: validFile + ": OK",
+		},
+		{
+			name:       "Lint invalid file",
+			args:       []string{"lint", validFile, invalidFile},
+			wantCode:   1,
+			wantStdout: invalidFile + ": [test-flag] percentage: percentage should be between 0 and 100, got 120",
+			wantStderr: "1 invalid file(s)",
+		},
+		{
+			name:       "Lint file does not exist",
+			args:       []string{"lint", "not-a-file.yaml"},
+			wantCode:   1,
+			wantStdout: "not-a-file.yaml: open not-a-file.yaml",
+		},
+		{
+			name:       "Lint without file",
+			args:       []string{"lint"},
+			wantCode:   2,
+			wantStderr: "lint needs at least one file",
+		},
+		{
+			name:       "Eval with key",
+			args:       []string{"eval", "--flag", "test-flag", "--key", "random-key", validFile},
+			wantCode:   0,
+			wantStdout: `"variation": "True"`,
+		},
+		{
+			name:       "Eval with JSON user",
+			args:       []string{"eval", "--flag", "test-flag", "--user", `{"key": "other-key"}`, validFile},
+			wantCode:   0,
+			wantStdout: `"reason": "DEFAULT"`,
+		},
+		{
+			name:       "Eval with attributes",
+			args:       []string{"eval", "--flag", "new-flag", "--key", "random-key", "--attr", "beta=true", newFile},
+			wantCode:   0,
+			wantStdout: `"value": "B"`,
+		},
+		{
+			name:       "Eval unknown flag",
+			args:       []string{"eval", "--flag", "unknown-flag", "--key", "random-key", validFile},
+			wantCode:   1,
+			wantStderr: "flag unknown-flag is not present or disabled",
+		},
+		{
+			name:       "Eval disabled flag",
+			args:       []string{"eval", "--flag", "disabled-flag", "--key", "random-key", "--default", `"off"`, disabledFile},
+			wantCode:   0,
+			wantStdout: "{\n  \"value\": \"off\",\n  \"variation\": \"SdkDefault\",\n  \"reason\": \"DISABLED\"\n}",
+		},
+		{
+			name:       "Eval with invalid default",
+			args:       []string{"eval", "--flag", "test-flag", "--key", "random-key", "--default", "sdk-default", validFile},
+			wantCode:   2,
+			wantStderr: "invalid --default",
+		},
+		{
+			name:       "Eval without user",
+			args:       []string{"eval", "--flag", "test-flag", validFile},
+			wantCode:   2,
+			wantStderr: "the user needs a key",
+		},
+		{
+			name:       "Eval with invalid attribute",
+			args:       []string{"eval", "--flag", "test-flag", "--key", "random-key", "--attr", "beta", validFile},
+			wantCode:   2,
+			wantStderr: `attribute "beta" should be name=value`,
+		},
+		{
+			name:       "Diff",
+			args:       []string{"diff", validFile, newFile},
+			wantCode:   0,
+			wantStdout: "- flag test-flag2 removed\n+ flag new-flag added\n",
+		},
+		{
+			name:       "Diff same file",
+			args:       []string{"diff", validFile, validFile},
+			wantCode:   0,
+			wantStdout: "no difference",
+		},
+		{
+			name:       "Diff same flags in another format",
+			args:       []string{"diff", intFileYAML, intFileTOML},
+			wantCode:   0,
+			wantStdout: "no difference",
+		},
+		{
+			name:       "Diff invalid file",
+			args:       []string{"diff", validFile, invalidFile},
+			wantCode:   1,
+			wantStderr: invalidFile,
+		},
+		{
+			name:       "Convert to TOML",
+			args:       []string{"convert", "--to", "toml", validFile},
+			wantCode:   0,
+			wantStdout: "[test-flag]\n  default = false\n",
+		},
+		{
+			name:       "Convert without target format",
+			args:       []string{"convert", validFile},
+			wantCode:   2,
+			wantStderr: "convert needs --to or --output",
+		},
+		{
+			name:       "Convert to unknown format",
+			args:       []string{"convert", "--to", "xml", validFile},
+			wantCode:   1,
+			wantStderr: `unknown file format "xml"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			code := run(tt.args, stdout, stderr)
+			assert.Equal(t, tt.wantCode, code, "stdout: %s, stderr: %s", stdout, stderr)
+			assert.Contains(t, stdout.String(), tt.wantStdout)
+			assert.Contains(t, stderr.String(), tt.wantStderr)
+		})
+	}
+}
+
+func TestRunConvertOutput(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goff")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	output := filepath.Join(dir, "flags.json")
+	code := run([]string{"convert", "--output", output, "../../testdata/flag-config.toml"}, ioutil.Discard, ioutil.Discard)
+	assert.Equal(t, 0, code)
+
+	stdout := &bytes.Buffer{}
+	code = run([]string{"diff", "../../testdata/flag-config.toml", output}, stdout, ioutil.Discard)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "no difference\n", stdout.String())
+}
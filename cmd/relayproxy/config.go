@@ -0,0 +1,244 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"gopkg.in/yaml.v3"
+
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+	"github.com/thomaspoignant/go-feature-flag/ffexporter"
+)
+
+// defaultListenAddress is the address of the relay proxy if listen is not set in the configuration.
+const defaultListenAddress = ":1031"
+
+// proxyConfig is the configuration file of the relay proxy, it maps the fields of ffclient.Config.
+type proxyConfig struct {
+	// Listen is the address where the relay proxy is listening (ex: ":1031").
+	Listen string `yaml:"listen"`
+
+	// GRPCListen (optional) is the address of the gRPC evaluation service (ex: ":1032"),
+	// the gRPC service is not started if it is empty.
+	GRPCListen string `yaml:"grpcListen"`
+
+	PollingInterval                 time.Duration     `yaml:"pollingInterval"`
+	FileFormat                      string            `yaml:"fileFormat"`
+	StartWithRetrieverError         bool              `yaml:"startWithRetrieverError"`
+	PersistentFlagConfigurationFile string            `yaml:"persistentFlagConfigurationFile"`
+	Retriever                       *retrieverConfig  `yaml:"retriever"`
+	Retrievers                      []retrieverConfig `yaml:"retrievers"`
+	Notifiers                       []notifierConfig  `yaml:"notifiers"`
+	Exporter                        *exporterConfig   `yaml:"exporter"`
+}
+
+// retrieverConfig is the configuration of a retriever, kind is one of file, directory, http, s3 or github.
+type retrieverConfig struct {
+	Kind           string            `yaml:"kind"`
+	Path           string            `yaml:"path"`
+	Watch          bool              `yaml:"watch"`
+	Pattern        string            `yaml:"pattern"`
+	URL            string            `yaml:"url"`
+	Method         string            `yaml:"method"`
+	Body           string            `yaml:"body"`
+	Header         map[string]string `yaml:"header"`
+	Timeout        time.Duration     `yaml:"timeout"`
+	Bucket         string            `yaml:"bucket"`
+	Item           string            `yaml:"item"`
+	AwsRegion      string            `yaml:"awsRegion"`
+	RepositorySlug string            `yaml:"repositorySlug"`
+	Branch         string            `yaml:"branch"`
+	GithubToken    string            `yaml:"githubToken"`
+	FileFormat     string            `yaml:"fileFormat"`
+}
+
+// notifierConfig is the configuration of a notifier, kind is one of slack or webhook.
+type notifierConfig struct {
+	Kind            string            `yaml:"kind"`
+	SlackWebhookURL string            `yaml:"slackWebhookUrl"`
+	EndpointURL     string            `yaml:"endpointUrl"`
+	Secret          string            `yaml:"secret"`
+	Meta            map[string]string `yaml:"meta"`
+}
+
+// exporterConfig is the configuration of the data exporter, kind is one of file, log, s3 or webhook.
+type exporterConfig struct {
+	Kind             string            `yaml:"kind"`
+	FlushInterval    time.Duration     `yaml:"flushInterval"`
+	MaxEventInMemory int64             `yaml:"maxEventInMemory"`
+	Format           string            `yaml:"format"`
+	OutputDir        string            `yaml:"outputDir"`
+	Filename         string            `yaml:"filename"`
+	CsvTemplate      string            `yaml:"csvTemplate"`
+	LogFormat        string            `yaml:"logFormat"`
+	Bucket           string            `yaml:"bucket"`
+	S3Path           string            `yaml:"s3Path"`
+	AwsRegion        string            `yaml:"awsRegion"`
+	EndpointURL      string            `yaml:"endpointUrl"`
+	Secret           string            `yaml:"secret"`
+	Meta             map[string]string `yaml:"meta"`
+}
+
+// loadConfig reads the configuration file of the relay proxy.
+func loadConfig(path string) (proxyConfig, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return proxyConfig{}, err
+	}
+
+	var config proxyConfig
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return proxyConfig{}, fmt.Errorf("invalid configuration file %s: %v", path, err)
+	}
+	if config.Listen == "" {
+		config.Listen = defaultListenAddress
+	}
+	if config.Retriever == nil && len(config.Retrievers) == 0 {
+		return proxyConfig{}, fmt.Errorf("invalid configuration file %s: no retriever", path)
+	}
+	return config, nil
+}
+
+// ffConfig converts the configuration of the relay proxy in a configuration for go-feature-flag.
+func (c proxyConfig) ffConfig(logger *log.Logger) (ffclient.Config, error) {
+	config := ffclient.Config{
+		PollingInterval:                 c.PollingInterval,
+		Logger:                          logger,
+		FileFormat:                      c.FileFormat,
+		StartWithRetrieverError:         c.StartWithRetrieverError,
+		PersistentFlagConfigurationFile: c.PersistentFlagConfigurationFile,
+	}
+
+	if c.Retriever != nil {
+		r, err := c.Retriever.retriever()
+		if err != nil {
+			return ffclient.Config{}, err
+		}
+		config.Retriever = r
+	}
+	for _, retrieverConf := range c.Retrievers {
+		r, err := retrieverConf.retriever()
+		if err != nil {
+			return ffclient.Config{}, err
+		}
+		config.Retrievers = append(config.Retrievers, r)
+	}
+
+	for _, notifierConf := range c.Notifiers {
+		n, err := notifierConf.notifier()
+		if err != nil {
+			return ffclient.Config{}, err
+		}
+		config.Notifiers = append(config.Notifiers, n)
+	}
+
+	if c.Exporter != nil {
+		dataExporter, err := c.Exporter.dataExporter()
+		if err != nil {
+			return ffclient.Config{}, err
+		}
+		config.DataExporter = dataExporter
+	}
+	return config, nil
+}
+
+// retriever converts the configuration in one of the retrievers of go-feature-flag.
+func (r retrieverConfig) retriever() (ffclient.Retriever, error) {
+	switch strings.ToLower(r.Kind) {
+	case "file":
+		return &ffclient.FileRetriever{Path: r.Path, Watch: r.Watch, FileFormat: r.FileFormat}, nil
+	case "directory":
+		return &ffclient.DirectoryRetriever{Path: r.Path, Pattern: r.Pattern}, nil
+	case "http":
+		header := http.Header{}
+		for name, value := range r.Header {
+			header.Set(name, value)
+		}
+		return &ffclient.HTTPRetriever{
+			URL:        r.URL,
+			Method:     r.Method,
+			Body:       r.Body,
+			Header:     header,
+			Timeout:    r.Timeout,
+			FileFormat: r.FileFormat,
+		}, nil
+	case "s3":
+		return &ffclient.S3Retriever{
+			Bucket:     r.Bucket,
+			Item:       r.Item,
+			AwsConfig:  awsConfig(r.AwsRegion),
+			FileFormat: r.FileFormat,
+		}, nil
+	case "github":
+		return &ffclient.GithubRetriever{
+			RepositorySlug: r.RepositorySlug,
+			Branch:         r.Branch,
+			FilePath:       r.Path,
+			GithubToken:    r.GithubToken,
+			Timeout:        r.Timeout,
+			FileFormat:     r.FileFormat,
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid retriever kind %q", r.Kind)
+	}
+}
+
+// notifier converts the configuration in one of the notifiers of go-feature-flag.
+func (n notifierConfig) notifier() (ffclient.NotifierConfig, error) {
+	switch strings.ToLower(n.Kind) {
+	case "slack":
+		return &ffclient.SlackNotifier{SlackWebhookURL: n.SlackWebhookURL}, nil
+	case "webhook":
+		return &ffclient.WebhookConfig{EndpointURL: n.EndpointURL, Secret: n.Secret, Meta: n.Meta}, nil
+	default:
+		return nil, fmt.Errorf("invalid notifier kind %q", n.Kind)
+	}
+}
+
+// dataExporter converts the configuration in one of the exporters of go-feature-flag.
+func (e exporterConfig) dataExporter() (ffclient.DataExporter, error) {
+	dataExporter := ffclient.DataExporter{
+		FlushInterval:    e.FlushInterval,
+		MaxEventInMemory: e.MaxEventInMemory,
+	}
+	switch strings.ToLower(e.Kind) {
+	case "file":
+		dataExporter.Exporter = &ffexporter.File{
+			Format:      e.Format,
+			OutputDir:   e.OutputDir,
+			Filename:    e.Filename,
+			CsvTemplate: e.CsvTemplate,
+		}
+	case "log":
+		dataExporter.Exporter = &ffexporter.Log{Format: e.LogFormat}
+	case "s3":
+		config := awsConfig(e.AwsRegion)
+		dataExporter.Exporter = &ffexporter.S3{
+			Bucket:      e.Bucket,
+			AwsConfig:   &config,
+			Format:      e.Format,
+			S3Path:      e.S3Path,
+			Filename:    e.Filename,
+			CsvTemplate: e.CsvTemplate,
+		}
+	case "webhook":
+		dataExporter.Exporter = &ffexporter.Webhook{EndpointURL: e.EndpointURL, Secret: e.Secret, Meta: e.Meta}
+	default:
+		return ffclient.DataExporter{}, fmt.Errorf("invalid exporter kind %q", e.Kind)
+	}
+	return dataExporter, nil
+}
+
+// awsConfig returns the AWS configuration for the region, the credentials are read from the environment.
+func awsConfig(region string) aws.Config {
+	config := aws.Config{}
+	if region != "" {
+		config.Region = aws.String(region)
+	}
+	return config
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+	"github.com/thomaspoignant/go-feature-flag/ffexporter"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "relayproxy")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name    string
+		content string
+		want    proxyConfig
+		wantErr string
+	}{
+		{
+			name: "Full configuration",
+			content: `listen: ":8080"
+pollingInterval: 10s
+fileFormat: json
+startWithRetrieverError: true
+retriever:
+  kind: http
+  url: https://example.com/flags.json
+  header:
+    Authorization: Bearer token
+  timeout: 2s
+retrievers:
+  - kind: file
+    path: flags.yaml
+    watch: true
+notifiers:
+  - kind: slack
+    slackWebhookUrl: https://hooks.slack.com/services/xxx
+exporter:
+  kind: log
+  flushInterval: 1m
+`,
+			want: proxyConfig{
+				Listen:                  ":8080",
+				PollingInterval:         10 * time.Second,
+				FileFormat:              "json",
+				StartWithRetrieverError: true,
+				Retriever: &retrieverConfig{
+					Kind:    "http",
+					URL:     "https://example.com/flags.json",
+					Header:  map[string]string{"Authorization": "Bearer token"},
+					Timeout: 2 * time.Second,
+				},
+				Retrievers: []retrieverConfig{{Kind: "file", Path: "flags.yaml", Watch: true}},
+				Notifiers:  []notifierConfig{{Kind: "slack", SlackWebhookURL: "https://hooks.slack.com/services/xxx"}},
+				Exporter:   &exporterConfig{Kind: "log", FlushInterval: time.Minute},
+			},
+		},
+		{
+			name:    "Default listen address",
+			content: "retriever:\n  kind: file\n  path: flags.yaml\n",
+			want: proxyConfig{
+				Listen:    defaultListenAddress,
+				Retriever: &retrieverConfig{Kind: "file", Path: "flags.yaml"},
+			},
+		},
+		{
+			name:    "No retriever",
+			content: "listen: \":8080\"\n",
+			wantErr: "no retriever",
+		},
+		{
+			name:    "Invalid YAML",
+			content: "listen: [",
+			wantErr: "invalid configuration file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, "goff-proxy.yaml")
+			assert.NoError(t, ioutil.WriteFile(path, []byte(tt.content), 0600))
+
+			got, err := loadConfig(path)
+			if tt.wantErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestProxyConfig_ffConfig(t *testing.T) {
+	logger := log.New(ioutil.Discard, "", 0)
+	conf := proxyConfig{
+		PollingInterval: 10 * time.Second,
+		Retriever: &retrieverConfig{
+			Kind:   "http",
+			URL:    "https://example.com/flags.yaml",
+			Header: map[string]string{"authorization": "Bearer token"},
+		},
+		Retrievers: []retrieverConfig{
+			{Kind: "s3", Bucket: "bucket", Item: "flags.yaml", AwsRegion: "eu-west-1"},
+			{Kind: "github", RepositorySlug: "thomaspoignant/go-feature-flag", Path: "testdata/flag-config.yaml"},
+			{Kind: "directory", Path: "flags/", Pattern: "*.yaml"},
+		},
+		Notifiers: []notifierConfig{
+			{Kind: "webhook", EndpointURL: "https://example.com/hook", Secret: "secret"},
+		},
+		Exporter: &exporterConfig{Kind: "file", OutputDir: "/tmp/", Format: "csv", MaxEventInMemory: 100},
+	}
+
+	got, err := conf.ffConfig(logger)
+	assert.NoError(t, err)
+	assert.Equal(t, ffclient.Config{
+		PollingInterval: 10 * time.Second,
+		Logger:          logger,
+		Retriever: &ffclient.HTTPRetriever{
+			URL:    "https://example.com/flags.yaml",
+			Header: http.Header{"Authorization": []string{"Bearer token"}},
+		},
+		Retrievers: []ffclient.Retriever{
+			&ffclient.S3Retriever{Bucket: "bucket", Item: "flags.yaml", AwsConfig: aws.Config{Region: aws.String("eu-west-1")}},
+			&ffclient.GithubRetriever{RepositorySlug: "thomaspoignant/go-feature-flag", FilePath: "testdata/flag-config.yaml"},
+			&ffclient.DirectoryRetriever{Path: "flags/", Pattern: "*.yaml"},
+		},
+		Notifiers: []ffclient.NotifierConfig{
+			&ffclient.WebhookConfig{EndpointURL: "https://example.com/hook", Secret: "secret"},
+		},
+		DataExporter: ffclient.DataExporter{
+			MaxEventInMemory: 100,
+			Exporter:         &ffexporter.File{OutputDir: "/tmp/", Format: "csv"},
+		},
+	}, got)
+}
+
+func TestProxyConfig_ffConfigInvalidKind(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    proxyConfig
+		wantErr string
+	}{
+		{
+			name:    "Invalid retriever",
+			conf:    proxyConfig{Retriever: &retrieverConfig{Kind: "ftp"}},
+			wantErr: `invalid retriever kind "ftp"`,
+		},
+		{
+			name:    "Invalid notifier",
+			conf:    proxyConfig{Notifiers: []notifierConfig{{Kind: "email"}}},
+			wantErr: `invalid notifier kind "email"`,
+		},
+		{
+			name:    "Invalid exporter",
+			conf:    proxyConfig{Exporter: &exporterConfig{Kind: "kafka"}},
+			wantErr: `invalid exporter kind "kafka"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.conf.ffConfig(nil)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/json"
+	"sync"
+
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+	"github.com/thomaspoignant/go-feature-flag/internal/model"
+	"github.com/thomaspoignant/go-feature-flag/internal/notifier"
+)
+
+const (
+	// eventHistorySize is the number of diff events kept to replay them to the clients reconnecting.
+	eventHistorySize = 100
+
+	// subscriberBufferSize is the number of events waiting for a client, a client too slow to read
+	// its events is disconnected and has to reconnect.
+	subscriberBufferSize = 16
+)
+
+// Names of the events sent to the clients.
+const (
+	eventSnapshot = "snapshot"
+	eventDiff     = "diff"
+)
+
+// flagEvent is an event sent to the clients connected to the stream of flag changes.
+type flagEvent struct {
+	ID   uint64
+	Name string
+	Data []byte
+}
+
+// flagSnapshot is the content of the snapshot event, it contains all the flags and segments.
+type flagSnapshot struct {
+	Flags    map[string]model.Flag    `json:"flags"`
+	Segments map[string]model.Segment `json:"segments"`
+}
+
+// flagEventBroadcaster is a notifier sending the changes of the flags to all the clients connected to the stream.
+// It keeps the current flags to send a snapshot to the new clients and the last events to replay them
+// to the clients reconnecting with a Last-Event-ID.
+type flagEventBroadcaster struct {
+	mutex       sync.Mutex
+	lastID      uint64
+	history     []flagEvent
+	flags       map[string]model.Flag
+	segments    map[string]model.Segment
+	subscribers map[chan flagEvent]struct{}
+	closed      bool
+}
+
+func newFlagEventBroadcaster() *flagEventBroadcaster {
+	return &flagEventBroadcaster{
+		flags:       map[string]model.Flag{},
+		segments:    map[string]model.Segment{},
+		subscribers: map[chan flagEvent]struct{}{},
+	}
+}
+
+// GetNotifier allows to use the broadcaster in the Notifiers of the configuration of go-feature-flag.
+func (b *flagEventBroadcaster) GetNotifier(_ ffclient.Config) (notifier.Notifier, error) {
+	return b, nil
+}
+
+// Notify is called by go-feature-flag every time the flags have changed.
+// The diffs are received in the order of the updates of the cache, applying them gives the current flags.
+func (b *flagEventBroadcaster) Notify(diff model.DiffCache, wg *sync.WaitGroup) error {
+	defer wg.Done()
+	data, err := json.Marshal(diff)
+	if err != nil {
+		return err
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.apply(diff)
+	b.lastID++
+	event := flagEvent{ID: b.lastID, Name: eventDiff, Data: data}
+	b.history = append(b.history, event)
+	if len(b.history) > eventHistorySize {
+		b.history = b.history[len(b.history)-eventHistorySize:]
+	}
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			// the client is too slow, we disconnect it and it will reconnect with its Last-Event-ID.
+			b.unsubscribeLocked(subscriber)
+		}
+	}
+	return nil
+}
+
+// apply updates the current flags with the diff.
+func (b *flagEventBroadcaster) apply(diff model.DiffCache) {
+	for key := range diff.Deleted {
+		delete(b.flags, key)
+	}
+	for key, flag := range diff.Added {
+		b.flags[key] = flag
+	}
+	for key, updated := range diff.Updated {
+		b.flags[key] = updated.After
+	}
+	if diff.Segments == nil {
+		return
+	}
+	for key := range diff.Segments.Deleted {
+		delete(b.segments, key)
+	}
+	for key, segment := range diff.Segments.Added {
+		b.segments[key] = segment
+	}
+	for key, updated := range diff.Segments.Updated {
+		b.segments[key] = updated.After
+	}
+}
+
+// subscribe registers a new client, it returns the first events to send to the client and the channel
+// receiving the next ones.
+// If the client is reconnecting and the events after lastEventID are still known, we replay them,
+// otherwise the client receives a snapshot of all the flags.
+func (b *flagEventBroadcaster) subscribe(lastEventID *uint64) ([]flagEvent, chan flagEvent, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	subscriber := make(chan flagEvent, subscriberBufferSize)
+	if b.closed {
+		close(subscriber)
+		return nil, subscriber, nil
+	}
+	b.subscribers[subscriber] = struct{}{}
+
+	if events, ok := b.eventsAfterLocked(lastEventID); ok {
+		return events, subscriber, nil
+	}
+	data, err := json.Marshal(flagSnapshot{Flags: b.flags, Segments: b.segments})
+	if err != nil {
+		b.unsubscribeLocked(subscriber)
+		return nil, nil, err
+	}
+	return []flagEvent{{ID: b.lastID, Name: eventSnapshot, Data: data}}, subscriber, nil
+}
+
+// eventsAfterLocked returns the events after lastEventID, false if some of them are not in the history anymore.
+func (b *flagEventBroadcaster) eventsAfterLocked(lastEventID *uint64) ([]flagEvent, bool) {
+	if lastEventID == nil || *lastEventID > b.lastID {
+		return nil, false
+	}
+	if *lastEventID == b.lastID {
+		return nil, true
+	}
+	if len(b.history) == 0 || b.history[0].ID > *lastEventID+1 {
+		return nil, false
+	}
+	events := make([]flagEvent, 0, b.lastID-*lastEventID)
+	for _, event := range b.history {
+		if event.ID > *lastEventID {
+			events = append(events, event)
+		}
+	}
+	return events, true
+}
+
+// unsubscribe removes a client, its channel is closed.
+func (b *flagEventBroadcaster) unsubscribe(subscriber chan flagEvent) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.unsubscribeLocked(subscriber)
+}
+
+func (b *flagEventBroadcaster) unsubscribeLocked(subscriber chan flagEvent) {
+	if _, ok := b.subscribers[subscriber]; ok {
+		delete(b.subscribers, subscriber)
+		close(subscriber)
+	}
+}
+
+// close disconnects all the clients, it is called when the relay proxy stops.
+func (b *flagEventBroadcaster) close() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.closed = true
+	for subscriber := range b.subscribers {
+		b.unsubscribeLocked(subscriber)
+	}
+}
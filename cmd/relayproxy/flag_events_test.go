@@ -0,0 +1,131 @@
+package main
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thomaspoignant/go-feature-flag/internal/model"
+	"github.com/thomaspoignant/go-feature-flag/testutils/testconvert"
+)
+
+func notify(b *flagEventBroadcaster, diff model.DiffCache) {
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	b.Notify(diff, wg)
+	wg.Wait()
+}
+
+func uint64Ptr(v uint64) *uint64 {
+	return &v
+}
+
+func TestFlagEventBroadcaster(t *testing.T) {
+	b := newFlagEventBroadcaster()
+	notify(b, model.DiffCache{
+		Added: map[string]model.Flag{
+			"flag-a": &model.FlagData{Percentage: testconvert.Float64(100)},
+			"flag-b": &model.FlagData{Percentage: testconvert.Float64(0)},
+		},
+		Segments: &model.DiffSegments{Added: map[string]model.Segment{"beta": {Included: []string{"user-1"}}}},
+	})
+	notify(b, model.DiffCache{
+		Deleted: map[string]model.Flag{"flag-b": &model.FlagData{}},
+		Updated: map[string]model.DiffUpdated{
+			"flag-a": {Before: &model.FlagData{}, After: &model.FlagData{Percentage: testconvert.Float64(50)}},
+		},
+	})
+
+	t.Run("New client receives a snapshot", func(t *testing.T) {
+		events, subscriber, err := b.subscribe(nil)
+		assert.NoError(t, err)
+		defer b.unsubscribe(subscriber)
+		assert.Len(t, events, 1)
+		assert.Equal(t, uint64(2), events[0].ID)
+		assert.Equal(t, eventSnapshot, events[0].Name)
+		assert.JSONEq(t, `{"flags": {"flag-a": {"percentage": 50}}, "segments": {"beta": {"included": ["user-1"]}}}`,
+			string(events[0].Data))
+	})
+
+	t.Run("Reconnecting client receives the missed events", func(t *testing.T) {
+		events, subscriber, err := b.subscribe(uint64Ptr(1))
+		assert.NoError(t, err)
+		defer b.unsubscribe(subscriber)
+		assert.Len(t, events, 1)
+		assert.Equal(t, uint64(2), events[0].ID)
+		assert.Equal(t, eventDiff, events[0].Name)
+	})
+
+	t.Run("Up to date client receives nothing", func(t *testing.T) {
+		events, subscriber, err := b.subscribe(uint64Ptr(2))
+		assert.NoError(t, err)
+		defer b.unsubscribe(subscriber)
+		assert.Empty(t, events)
+	})
+
+	t.Run("Unknown last event id receives a snapshot", func(t *testing.T) {
+		events, subscriber, err := b.subscribe(uint64Ptr(42))
+		assert.NoError(t, err)
+		defer b.unsubscribe(subscriber)
+		assert.Len(t, events, 1)
+		assert.Equal(t, eventSnapshot, events[0].Name)
+	})
+
+	t.Run("Connected client receives the next events", func(t *testing.T) {
+		_, subscriber, err := b.subscribe(uint64Ptr(2))
+		assert.NoError(t, err)
+		defer b.unsubscribe(subscriber)
+		notify(b, model.DiffCache{Added: map[string]model.Flag{"flag-c": &model.FlagData{}}})
+		event := <-subscriber
+		assert.Equal(t, uint64(3), event.ID)
+		assert.Equal(t, eventDiff, event.Name)
+	})
+}
+
+func TestFlagEventBroadcaster_historyOverflow(t *testing.T) {
+	b := newFlagEventBroadcaster()
+	for i := 0; i < eventHistorySize+10; i++ {
+		notify(b, model.DiffCache{Added: map[string]model.Flag{"flag": &model.FlagData{}}})
+	}
+
+	events, subscriber, err := b.subscribe(uint64Ptr(5))
+	assert.NoError(t, err)
+	defer b.unsubscribe(subscriber)
+	assert.Len(t, events, 1)
+	assert.Equal(t, eventSnapshot, events[0].Name)
+
+	events, subscriber2, err := b.subscribe(uint64Ptr(10))
+	assert.NoError(t, err)
+	defer b.unsubscribe(subscriber2)
+	assert.Len(t, events, eventHistorySize)
+}
+
+func TestFlagEventBroadcaster_slowClient(t *testing.T) {
+	b := newFlagEventBroadcaster()
+	_, subscriber, err := b.subscribe(nil)
+	assert.NoError(t, err)
+	for i := 0; i < subscriberBufferSize+1; i++ {
+		notify(b, model.DiffCache{Added: map[string]model.Flag{"flag": &model.FlagData{}}})
+	}
+
+	received := 0
+	for range subscriber {
+		received++
+	}
+	assert.Equal(t, subscriberBufferSize, received)
+}
+
+func TestFlagEventBroadcaster_close(t *testing.T) {
+	b := newFlagEventBroadcaster()
+	_, subscriber, err := b.subscribe(nil)
+	assert.NoError(t, err)
+	b.close()
+	_, ok := <-subscriber
+	assert.False(t, ok)
+
+	_, subscriber, err = b.subscribe(nil)
+	assert.NoError(t, err)
+	_, ok = <-subscriber
+	assert.False(t, ok)
+}
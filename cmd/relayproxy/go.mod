@@ -0,0 +1,19 @@
+module github.com/thomaspoignant/go-feature-flag/cmd/relayproxy
+
+go 1.15
+
+require (
+	github.com/aws/aws-sdk-go v1.38.30
+	github.com/stretchr/testify v1.7.0
+	github.com/thomaspoignant/go-feature-flag v0.0.0-00010101000000-000000000000
+	github.com/thomaspoignant/go-feature-flag/ffgrpc v0.0.0-00010101000000-000000000000
+	google.golang.org/grpc v1.38.0
+	google.golang.org/protobuf v1.26.0
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c
+)
+
+// the relay proxy is released with go-feature-flag, it always uses the version of the same commit.
+replace (
+	github.com/thomaspoignant/go-feature-flag => ../../
+	github.com/thomaspoignant/go-feature-flag/ffgrpc => ../../ffgrpc
+)
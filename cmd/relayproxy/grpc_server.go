@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+	"github.com/thomaspoignant/go-feature-flag/ffgrpc"
+	"github.com/thomaspoignant/go-feature-flag/ffuser"
+)
+
+// grpcServer is the gRPC evaluation service of the relay proxy, it is the equivalent of the REST endpoints.
+type grpcServer struct {
+	ffgrpc.UnimplementedEvaluationServiceServer
+	goff   flagEvaluator
+	events *flagEventBroadcaster
+}
+
+// EvaluateFlag evaluates one flag for the user of the request.
+func (s *grpcServer) EvaluateFlag(
+	ctx context.Context, req *ffgrpc.EvaluateFlagRequest) (*ffgrpc.EvaluateFlagResponse, error) {
+	user, err := readProtoUser(req.GetUser())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetFlagKey() == "" {
+		return nil, status.Error(codes.InvalidArgument, "the flag key is mandatory")
+	}
+
+	details, evalErr := s.goff.RawVariationDetailsCtx(ctx, req.GetFlagKey(), user, req.GetDefaultValue().AsInterface())
+	value, err := newProtoValue(details.Value)
+	if err != nil {
+		// the value of the flag cannot be sent, the caller gets its default value.
+		return &ffgrpc.EvaluateFlagResponse{
+			Value:        req.GetDefaultValue(),
+			Reason:       string(ffclient.ReasonError),
+			ErrorCode:    string(ffclient.ErrorCodeGeneral),
+			Failed:       true,
+			ErrorMessage: fmt.Sprintf("invalid value for the flag %s: %v", req.GetFlagKey(), err),
+		}, nil
+	}
+	resp := &ffgrpc.EvaluateFlagResponse{
+		Value:     value,
+		Variation: details.Variation,
+		Reason:    string(details.Reason),
+		ErrorCode: string(details.ErrorCode),
+		RuleName:  details.RuleName,
+		Failed:    evalErr != nil,
+	}
+	if evalErr != nil {
+		resp.ErrorMessage = evalErr.Error()
+	}
+	return resp, nil
+}
+
+// EvaluateAllFlags evaluates all the flags for the user of the request.
+func (s *grpcServer) EvaluateAllFlags(
+	_ context.Context, req *ffgrpc.EvaluateAllFlagsRequest) (*ffgrpc.EvaluateAllFlagsResponse, error) {
+	user, err := readProtoUser(req.GetUser())
+	if err != nil {
+		return nil, err
+	}
+
+	allFlags := s.goff.AllFlagsState(user)
+	resp := &ffgrpc.EvaluateAllFlagsResponse{
+		Flags: make(map[string]*ffgrpc.FlagState, len(allFlags.Flags)),
+		Valid: allFlags.Valid,
+	}
+	for key, state := range allFlags.Flags {
+		value, err := newProtoValue(state.Value)
+		if err != nil {
+			// only this flag is in error, the other flags are still sent.
+			resp.Flags[key] = &ffgrpc.FlagState{
+				Value:       structpb.NewNullValue(),
+				Reason:      string(ffclient.ReasonError),
+				TrackEvents: state.TrackEvents,
+				ClientSide:  state.ClientSide,
+			}
+			continue
+		}
+		resp.Flags[key] = &ffgrpc.FlagState{
+			Value:       value,
+			Variation:   state.Variation,
+			Reason:      string(state.Reason),
+			RuleName:    state.RuleName,
+			TrackEvents: state.TrackEvents,
+			ClientSide:  state.ClientSide,
+		}
+	}
+	return resp, nil
+}
+
+// WatchFlags streams the changes of the flags, it sends the same events as the Server-Sent Events stream.
+func (s *grpcServer) WatchFlags(req *ffgrpc.WatchFlagsRequest, stream ffgrpc.EvaluationService_WatchFlagsServer) error {
+	var lastEventID *uint64
+	if req.GetLastEventId() != 0 {
+		id := req.GetLastEventId()
+		lastEventID = &id
+	}
+	events, subscriber, err := s.events.subscribe(lastEventID)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	defer s.events.unsubscribe(subscriber)
+
+	for _, event := range events {
+		if err := stream.Send(&ffgrpc.FlagEvent{Id: event.ID, Name: event.Name, Data: event.Data}); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-subscriber:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(&ffgrpc.FlagEvent{Id: event.ID, Name: event.Name, Data: event.Data}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// newProtoValue converts the value of a flag, a value that is not a JSON type (ex: a TOML date) is
+// converted to its JSON representation, as in the REST API.
+func newProtoValue(value interface{}) (*structpb.Value, error) {
+	if protoValue, err := structpb.NewValue(value); err == nil {
+		return protoValue, nil
+	}
+	content, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var jsonValue interface{}
+	if err := json.Unmarshal(content, &jsonValue); err != nil {
+		return nil, err
+	}
+	return structpb.NewValue(jsonValue)
+}
+
+// readProtoUser converts the user of a request, the user needs a key.
+func readProtoUser(protoUser *ffgrpc.User) (ffuser.User, error) {
+	user, err := ffgrpc.UserFromProto(protoUser)
+	if err != nil {
+		return ffuser.User{}, status.Errorf(codes.InvalidArgument, "invalid user: %v", err)
+	}
+	if user.GetKey() == "" {
+		return ffuser.User{}, status.Error(codes.InvalidArgument, "invalid user: the user needs a key")
+	}
+	return user, nil
+}
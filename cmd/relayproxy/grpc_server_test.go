@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+	"github.com/thomaspoignant/go-feature-flag/ffgrpc"
+	"github.com/thomaspoignant/go-feature-flag/ffuser"
+	"github.com/thomaspoignant/go-feature-flag/internal/model"
+	"github.com/thomaspoignant/go-feature-flag/testutils/testconvert"
+)
+
+func TestGRPCServer(t *testing.T) {
+	goff, err := ffclient.New(ffclient.Config{
+		PollingInterval: 10 * time.Second,
+		Logger:          log.New(ioutil.Discard, "", 0),
+		Retriever:       &ffclient.FileRetriever{Path: "../../testdata/flag-config.yaml"},
+	})
+	assert.NoError(t, err)
+	defer goff.Close()
+
+	events := newFlagEventBroadcaster()
+	notify(events, model.DiffCache{
+		Added: map[string]model.Flag{"test-flag": &model.FlagData{Percentage: testconvert.Float64(100)}},
+	})
+	defer events.close()
+
+	listener := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	ffgrpc.RegisterEvaluationServiceServer(srv, &grpcServer{goff: goff, events: events})
+	go func() { _ = srv.Serve(listener) }()
+	defer srv.Stop()
+
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(),
+		grpc.WithContextDialer(func(_ context.Context, _ string) (net.Conn, error) {
+			return listener.Dial()
+		}))
+	assert.NoError(t, err)
+	defer conn.Close()
+	client := ffgrpc.NewEvaluationServiceClient(conn)
+	ctx := context.Background()
+
+	t.Run("Eval flag", func(t *testing.T) {
+		resp, err := client.EvaluateFlag(ctx, &ffgrpc.EvaluateFlagRequest{
+			FlagKey:      "test-flag",
+			User:         &ffgrpc.User{Key: "random-key"},
+			DefaultValue: structpb.NewBoolValue(false),
+		})
+		assert.NoError(t, err)
+		assert.True(t, resp.GetValue().GetBoolValue())
+		assert.Equal(t, "True", resp.GetVariation())
+		assert.Equal(t, "TARGETING_MATCH", resp.GetReason())
+		assert.False(t, resp.GetFailed())
+	})
+
+	t.Run("Eval unknown flag", func(t *testing.T) {
+		resp, err := client.EvaluateFlag(ctx, &ffgrpc.EvaluateFlagRequest{
+			FlagKey:      "unknown-flag",
+			User:         &ffgrpc.User{Key: "random-key"},
+			DefaultValue: structpb.NewStringValue("sdk-default"),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "sdk-default", resp.GetValue().GetStringValue())
+		assert.Equal(t, "FLAG_NOT_FOUND", resp.GetErrorCode())
+		assert.True(t, resp.GetFailed())
+		assert.NotEmpty(t, resp.GetErrorMessage())
+	})
+
+	t.Run("Eval without user key", func(t *testing.T) {
+		_, err := client.EvaluateFlag(ctx, &ffgrpc.EvaluateFlagRequest{
+			FlagKey: "test-flag",
+			User:    &ffgrpc.User{Anonymous: true},
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Eval without flag key", func(t *testing.T) {
+		_, err := client.EvaluateFlag(ctx, &ffgrpc.EvaluateFlagRequest{User: &ffgrpc.User{Key: "random-key"}})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("All flags", func(t *testing.T) {
+		resp, err := client.EvaluateAllFlags(ctx, &ffgrpc.EvaluateAllFlagsRequest{User: &ffgrpc.User{Key: "random-key"}})
+		assert.NoError(t, err)
+		assert.True(t, resp.GetValid())
+		assert.Len(t, resp.GetFlags(), 2)
+		assert.True(t, resp.GetFlags()["test-flag"].GetValue().GetBoolValue())
+		assert.False(t, resp.GetFlags()["test-flag2"].GetValue().GetBoolValue())
+	})
+
+	t.Run("Watch flags", func(t *testing.T) {
+		streamCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		stream, err := client.WatchFlags(streamCtx, &ffgrpc.WatchFlagsRequest{})
+		assert.NoError(t, err)
+		event, err := stream.Recv()
+		assert.NoError(t, err)
+		assert.Equal(t, "snapshot", event.GetName())
+		assert.Equal(t, uint64(1), event.GetId())
+		assert.Contains(t, string(event.GetData()), `"test-flag"`)
+
+		notify(events, model.DiffCache{
+			Deleted: map[string]model.Flag{"test-flag": &model.FlagData{}},
+		})
+		event, err = stream.Recv()
+		assert.NoError(t, err)
+		assert.Equal(t, "diff", event.GetName())
+		assert.Equal(t, uint64(2), event.GetId())
+	})
+
+	t.Run("Watch flags from an event", func(t *testing.T) {
+		stream, err := client.WatchFlags(ctx, &ffgrpc.WatchFlagsRequest{LastEventId: 1})
+		assert.NoError(t, err)
+		event, err := stream.Recv()
+		assert.NoError(t, err)
+		assert.Equal(t, "diff", event.GetName())
+		assert.Equal(t, uint64(2), event.GetId())
+	})
+}
+
+// valuesEvaluatorMock evaluates every flag to the value set in the mock for this flag.
+type valuesEvaluatorMock struct {
+	flagEvaluator
+	values map[string]interface{}
+}
+
+func (e valuesEvaluatorMock) RawVariationDetailsCtx(_ context.Context, flagKey string, _ ffuser.User,
+	_ interface{}) (ffclient.RawEvaluationDetails, error) {
+	return ffclient.RawEvaluationDetails{
+		Value:             e.values[flagKey],
+		EvaluationDetails: ffclient.EvaluationDetails{Variation: "True", Reason: ffclient.ReasonTargetingMatch},
+	}, nil
+}
+
+func (e valuesEvaluatorMock) AllFlagsState(_ ffuser.User) ffclient.AllFlags {
+	res := ffclient.AllFlags{Flags: make(map[string]ffclient.FlagState), Valid: true}
+	for key, value := range e.values {
+		res.Flags[key] = ffclient.FlagState{Value: value, Variation: "True", Reason: ffclient.ReasonTargetingMatch}
+	}
+	return res
+}
+
+func TestGRPCServer_invalidValues(t *testing.T) {
+	srv := &grpcServer{goff: valuesEvaluatorMock{values: map[string]interface{}{
+		"string-flag": "value",
+		"date-flag":   time.Date(1979, 5, 27, 7, 32, 0, 0, time.UTC),
+		"list-flag":   []string{"a", "b"},
+		"chan-flag":   make(chan int),
+	}}}
+	user := &ffgrpc.User{Key: "random-key"}
+
+	t.Run("Eval flags", func(t *testing.T) {
+		tests := []struct {
+			flagKey    string
+			wantValue  *structpb.Value
+			wantFailed bool
+		}{
+			{flagKey: "string-flag", wantValue: structpb.NewStringValue("value")},
+			{flagKey: "date-flag", wantValue: structpb.NewStringValue("1979-05-27T07:32:00Z")},
+			{flagKey: "list-flag", wantValue: structpb.NewListValue(&structpb.ListValue{Values: []*structpb.Value{
+				structpb.NewStringValue("a"), structpb.NewStringValue("b")}})},
+			{flagKey: "chan-flag", wantValue: structpb.NewStringValue("sdk-default"), wantFailed: true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.flagKey, func(t *testing.T) {
+				resp, err := srv.EvaluateFlag(context.Background(), &ffgrpc.EvaluateFlagRequest{
+					FlagKey:      tt.flagKey,
+					User:         user,
+					DefaultValue: structpb.NewStringValue("sdk-default"),
+				})
+				assert.NoError(t, err)
+				assert.True(t, proto.Equal(tt.wantValue, resp.GetValue()), "got %v", resp.GetValue())
+				assert.Equal(t, tt.wantFailed, resp.GetFailed())
+				if tt.wantFailed {
+					assert.Equal(t, "ERROR", resp.GetReason())
+					assert.Equal(t, "GENERAL", resp.GetErrorCode())
+				}
+			})
+		}
+	})
+
+	t.Run("All flags", func(t *testing.T) {
+		resp, err := srv.EvaluateAllFlags(context.Background(), &ffgrpc.EvaluateAllFlagsRequest{User: user})
+		assert.NoError(t, err)
+		assert.True(t, resp.GetValid())
+		assert.Len(t, resp.GetFlags(), 4, "the flag in error should not remove the other flags")
+		assert.Equal(t, "value", resp.GetFlags()["string-flag"].GetValue().GetStringValue())
+		assert.Equal(t, "1979-05-27T07:32:00Z", resp.GetFlags()["date-flag"].GetValue().GetStringValue())
+		assert.Equal(t, "TARGETING_MATCH", resp.GetFlags()["date-flag"].GetReason())
+		assert.Equal(t, "ERROR", resp.GetFlags()["chan-flag"].GetReason())
+		_, isNull := resp.GetFlags()["chan-flag"].GetValue().GetKind().(*structpb.Value_NullValue)
+		assert.True(t, isNull)
+	})
+}
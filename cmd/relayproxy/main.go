@@ -0,0 +1,105 @@
+// relayproxy is an HTTP server exposing the flags of go-feature-flag to the services
+// not written in Go.
+//
+// Usage:
+//
+//	relayproxy [--config goff-proxy.yaml]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+	"github.com/thomaspoignant/go-feature-flag/ffgrpc"
+)
+
+// shutdownTimeout is the time we wait for the requests in progress before stopping the server.
+const shutdownTimeout = 10 * time.Second
+
+func main() {
+	configPath := flag.String("config", "goff-proxy.yaml", "path of the configuration file")
+	flag.Parse()
+
+	logger := log.New(os.Stdout, "", log.LstdFlags)
+	if err := run(*configPath, logger); err != nil {
+		logger.Printf("error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run starts the relay proxy and blocks until the process receives SIGINT or SIGTERM.
+func run(configPath string, logger *log.Logger) error {
+	proxyConf, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	ffConf, err := proxyConf.ffConfig(logger)
+	if err != nil {
+		return err
+	}
+	// the broadcaster receives the changes of the flags to stream them to the clients.
+	events := newFlagEventBroadcaster()
+	ffConf.Notifiers = append(ffConf.Notifiers, events)
+	goff, err := ffclient.New(ffConf)
+	if err != nil {
+		return fmt.Errorf("impossible to start go-feature-flag: %v", err)
+	}
+	defer goff.Close()
+
+	srv := &http.Server{
+		Addr:              proxyConf.Listen,
+		Handler:           newServer(goff, events),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errChan := make(chan error, 2)
+	go func() {
+		logger.Printf("relay proxy listening on %s\n", proxyConf.Listen)
+		errChan <- srv.ListenAndServe()
+	}()
+
+	var grpcSrv *grpc.Server
+	if proxyConf.GRPCListen != "" {
+		listener, err := net.Listen("tcp", proxyConf.GRPCListen)
+		if err != nil {
+			return err
+		}
+		grpcSrv = grpc.NewServer()
+		ffgrpc.RegisterEvaluationServiceServer(grpcSrv, &grpcServer{goff: goff, events: events})
+		go func() {
+			logger.Printf("relay proxy gRPC service listening on %s\n", proxyConf.GRPCListen)
+			errChan <- grpcSrv.Serve(listener)
+		}()
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case err := <-errChan:
+		return err
+	case <-stop:
+	}
+
+	// the streams never end by themselves, we close them to be able to stop the servers.
+	events.close()
+	if grpcSrv != nil {
+		grpcSrv.GracefulStop()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+	"github.com/thomaspoignant/go-feature-flag/ffuser"
+)
+
+// maxBodySize is the maximum size of the body of a request.
+const maxBodySize = 1 << 20
+
+// sseRetry is the time the clients wait before reconnecting to the stream of flag changes.
+const sseRetry = 3 * time.Second
+
+// sseHeartbeat is the interval between 2 comments sent to keep the stream open through the proxies.
+const sseHeartbeat = 30 * time.Second
+
+// featurePrefix and evalSuffix surround the name of the flag in the URL of the evaluation endpoint.
+const (
+	featurePrefix = "/v1/feature/"
+	evalSuffix    = "/eval"
+)
+
+// flagEvaluator is the part of GoFeatureFlag used by the relay proxy.
+type flagEvaluator interface {
+	RawVariationDetailsCtx(ctx context.Context, flagKey string, user ffuser.User,
+		defaultValue interface{}) (ffclient.RawEvaluationDetails, error)
+	AllFlagsState(user ffuser.User) ffclient.AllFlags
+	GetCacheStatus() ffclient.CacheStatus
+}
+
+// evalRequest is the body of the evaluation requests.
+type evalRequest struct {
+	User         *ffuser.User `json:"user"`
+	DefaultValue interface{}  `json:"defaultValue"`
+}
+
+// evalResponse is the result of the evaluation of a flag.
+type evalResponse struct {
+	ffclient.RawEvaluationDetails
+
+	// Failed is true if the default value has been served because the evaluation failed.
+	Failed bool `json:"failed"`
+}
+
+// readyResponse is the body of the readiness endpoint.
+type readyResponse struct {
+	Ready              bool       `json:"ready"`
+	LastUpdate         *time.Time `json:"lastUpdate,omitempty"`
+	FromPersistentFile bool       `json:"fromPersistentFile"`
+}
+
+// errorResponse is the body returned when the request is invalid.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// newServer returns the handler of the relay proxy.
+func newServer(goff flagEvaluator, events *flagEventBroadcaster) http.Handler {
+	s := server{goff: goff, events: events}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", s.health)
+	mux.HandleFunc("/ready", s.ready)
+	mux.HandleFunc("/v1/allflags", s.allFlags)
+	mux.HandleFunc("/v1/flags/stream", s.streamFlagEvents)
+	mux.HandleFunc(featurePrefix, s.evalFlag)
+	return mux
+}
+
+type server struct {
+	goff   flagEvaluator
+	events *flagEventBroadcaster
+}
+
+// health is always OK when the relay proxy is running.
+func (s server) health(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"initialized": true})
+}
+
+// ready is OK when the flags have been retrieved at least once.
+func (s server) ready(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	status := s.goff.GetCacheStatus()
+	if status.LastUpdate.IsZero() {
+		writeJSON(w, http.StatusServiceUnavailable, readyResponse{})
+		return
+	}
+	writeJSON(w, http.StatusOK, readyResponse{
+		Ready:              true,
+		LastUpdate:         &status.LastUpdate,
+		FromPersistentFile: status.FromPersistentFile,
+	})
+}
+
+// allFlags evaluates all the flags for the user of the request.
+func (s server) allFlags(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	req, err := readEvalRequest(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.goff.AllFlagsState(*req.User))
+}
+
+// evalFlag evaluates the flag of the URL /v1/feature/<flag>/eval for the user of the request.
+func (s server) evalFlag(w http.ResponseWriter, r *http.Request) {
+	flagKey := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, featurePrefix), evalSuffix)
+	if !strings.HasSuffix(r.URL.Path, evalSuffix) || flagKey == "" || strings.Contains(flagKey, "/") {
+		http.NotFound(w, r)
+		return
+	}
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	req, err := readEvalRequest(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	details, err := s.goff.RawVariationDetailsCtx(r.Context(), flagKey, *req.User, req.DefaultValue)
+	writeJSON(w, http.StatusOK, evalResponse{RawEvaluationDetails: details, Failed: err != nil})
+}
+
+// streamFlagEvents sends the changes of the flags with Server-Sent Events.
+// A new client receives a snapshot of all the flags, a client reconnecting with the header Last-Event-ID
+// receives the changes it has missed (or a snapshot if they are too old).
+func (s server) streamFlagEvents(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming not supported"))
+		return
+	}
+	lastEventID, err := readLastEventID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	events, subscriber, err := s.events.subscribe(lastEventID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	defer s.events.unsubscribe(subscriber)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetry.Milliseconds())
+	for _, event := range events {
+		writeEvent(w, event)
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-subscriber:
+			if !ok {
+				return
+			}
+			writeEvent(w, event)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		}
+		flusher.Flush()
+	}
+}
+
+// readLastEventID reads the id of the last event received by a client reconnecting, nil for a new client.
+// The id can also be sent in the query parameter lastEventId for the clients not able to set the header.
+func readLastEventID(r *http.Request) (*uint64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("lastEventId")
+	}
+	if value == "" {
+		return nil, nil
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Last-Event-ID %q", value)
+	}
+	return &id, nil
+}
+
+// writeEvent writes an event in the Server-Sent Events format.
+func writeEvent(w io.Writer, event flagEvent) {
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Name, event.Data)
+}
+
+// readEvalRequest reads the body of an evaluation request, the user is mandatory.
+func readEvalRequest(w http.ResponseWriter, r *http.Request) (evalRequest, error) {
+	var req evalRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&req); err != nil {
+		return evalRequest{}, fmt.Errorf("invalid body: %v", err)
+	}
+	if req.User == nil || req.User.GetKey() == "" {
+		return evalRequest{}, errors.New("invalid body: the user needs a key")
+	}
+	return req, nil
+}
+
+// allowMethod writes a 405 error and returns false if the method of the request is not the expected one.
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+	return false
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
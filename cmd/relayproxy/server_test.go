@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bufio"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+)
+
+func TestServer(t *testing.T) {
+	goff, err := ffclient.New(ffclient.Config{
+		PollingInterval: 10 * time.Second,
+		Logger:          log.New(ioutil.Discard, "", 0),
+		Retriever:       &ffclient.FileRetriever{Path: "../../testdata/flag-config.yaml"},
+	})
+	assert.NoError(t, err)
+	defer goff.Close()
+	srv := httptest.NewServer(newServer(goff, newFlagEventBroadcaster()))
+	defer srv.Close()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "Health",
+			method:     http.MethodGet,
+			path:       "/health",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"initialized": true}`,
+		},
+		{
+			name:       "Eval flag",
+			method:     http.MethodPost,
+			path:       "/v1/feature/test-flag/eval",
+			body:       `{"user": {"key": "random-key"}, "defaultValue": false}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"value": true, "variation": "True", "reason": "TARGETING_MATCH", "failed": false}`,
+		},
+		{
+			name:       "Eval flag not matching the rule",
+			method:     http.MethodPost,
+			path:       "/v1/feature/test-flag/eval",
+			body:       `{"user": {"key": "other-key", "custom": {"beta": true}}, "defaultValue": false}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"value": false, "variation": "Default", "reason": "DEFAULT", "failed": false}`,
+		},
+		{
+			name:       "Eval unknown flag",
+			method:     http.MethodPost,
+			path:       "/v1/feature/unknown-flag/eval",
+			body:       `{"user": {"key": "random-key"}, "defaultValue": "sdk-default"}`,
+			wantStatus: http.StatusOK,
+			wantBody: `{"value": "sdk-default", "variation": "SdkDefault", "reason": "FLAG_NOT_FOUND",
+				"errorCode": "FLAG_NOT_FOUND", "failed": true}`,
+		},
+		{
+			name:       "Eval without user key",
+			method:     http.MethodPost,
+			path:       "/v1/feature/test-flag/eval",
+			body:       `{"user": {"anonymous": true}}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error": "invalid body: the user needs a key"}`,
+		},
+		{
+			name:       "Eval with invalid body",
+			method:     http.MethodPost,
+			path:       "/v1/feature/test-flag/eval",
+			body:       `{"user":`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error": "invalid body: unexpected EOF"}`,
+		},
+		{
+			name:       "Eval with GET",
+			method:     http.MethodGet,
+			path:       "/v1/feature/test-flag/eval",
+			wantStatus: http.StatusMethodNotAllowed,
+			wantBody:   `{"error": "method GET not allowed"}`,
+		},
+		{
+			name:       "All flags",
+			method:     http.MethodPost,
+			path:       "/v1/allflags",
+			body:       `{"user": {"key": "random-key"}}`,
+			wantStatus: http.StatusOK,
+			wantBody: `{"valid": true, "flags": {
+				"test-flag": {"value": true, "variation": "True", "reason": "TARGETING_MATCH", "trackEvents": true},
+				"test-flag2": {"value": false, "variation": "Default", "reason": "DEFAULT", "trackEvents": true}
+			}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, srv.URL+tt.path, strings.NewReader(tt.body))
+			assert.NoError(t, err)
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+			body, err := ioutil.ReadAll(resp.Body)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.JSONEq(t, tt.wantBody, string(body))
+		})
+	}
+
+	t.Run("Unknown path", func(t *testing.T) {
+		resp, err := http.Post(srv.URL+"/v1/feature/test-flag", "application/json", strings.NewReader(`{}`))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+type evaluatorMock struct {
+	flagEvaluator
+	status ffclient.CacheStatus
+}
+
+func (e evaluatorMock) GetCacheStatus() ffclient.CacheStatus {
+	return e.status
+}
+
+func TestServer_ready(t *testing.T) {
+	lastUpdate := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		status     ffclient.CacheStatus
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "Flags never retrieved",
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   `{"ready": false, "fromPersistentFile": false}`,
+		},
+		{
+			name:       "Flags retrieved",
+			status:     ffclient.CacheStatus{LastUpdate: lastUpdate},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"ready": true, "lastUpdate": "2021-05-01T10:00:00Z", "fromPersistentFile": false}`,
+		},
+		{
+			name:       "Flags from the persistent file",
+			status:     ffclient.CacheStatus{LastUpdate: lastUpdate, FromPersistentFile: true},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"ready": true, "lastUpdate": "2021-05-01T10:00:00Z", "fromPersistentFile": true}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			srv := newServer(evaluatorMock{status: tt.status}, newFlagEventBroadcaster())
+			srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.JSONEq(t, tt.wantBody, rec.Body.String())
+		})
+	}
+}
+
+// readEvent reads the next event of a Server-Sent Events stream, the comments are ignored.
+func readEvent(t *testing.T, reader *bufio.Reader) map[string]string {
+	event := map[string]string{}
+	for {
+		line, err := reader.ReadString('\n')
+		assert.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			if _, ok := event["event"]; ok {
+				return event
+			}
+			continue
+		}
+		if parts := strings.SplitN(line, ": ", 2); len(parts) == 2 && parts[0] != "" {
+			event[parts[0]] = parts[1]
+		}
+	}
+}
+
+func TestServer_streamFlagEvents(t *testing.T) {
+	dir, err := ioutil.TempDir("", "relayproxy")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	flagFile := filepath.Join(dir, "flags.yaml")
+	assert.NoError(t, ioutil.WriteFile(flagFile, []byte("test-flag:\n  percentage: 100\n  true: true\n"+
+		"  false: false\n  default: false\n"), 0600))
+
+	events := newFlagEventBroadcaster()
+	goff, err := ffclient.New(ffclient.Config{
+		PollingInterval: time.Second,
+		Retriever:       &ffclient.FileRetriever{Path: flagFile},
+		Notifiers:       []ffclient.NotifierConfig{events},
+	})
+	assert.NoError(t, err)
+	defer goff.Close()
+	srv := httptest.NewServer(newServer(goff, events))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/v1/flags/stream")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	reader := bufio.NewReader(resp.Body)
+
+	snapshot := readEvent(t, reader)
+	assert.Equal(t, "snapshot", snapshot["event"])
+	assert.Equal(t, "1", snapshot["id"])
+	assert.JSONEq(t, `{"flags": {"test-flag": {"percentage": 100, "true": true, "false": false, "default": false}},
+		"segments": {}}`, snapshot["data"])
+
+	assert.NoError(t, ioutil.WriteFile(flagFile, []byte("test-flag:\n  percentage: 50\n  true: true\n"+
+		"  false: false\n  default: false\n"), 0600))
+	diff := readEvent(t, reader)
+	assert.Equal(t, "diff", diff["event"])
+	assert.Equal(t, "2", diff["id"])
+	assert.Contains(t, diff["data"], `"percentage":50`)
+
+	t.Run("Reconnect with Last-Event-ID", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/v1/flags/stream", nil)
+		assert.NoError(t, err)
+		req.Header.Set("Last-Event-ID", "1")
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		event := readEvent(t, bufio.NewReader(resp.Body))
+		assert.Equal(t, "diff", event["event"])
+		assert.Equal(t, "2", event["id"])
+	})
+
+	t.Run("Invalid Last-Event-ID", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + "/v1/flags/stream?lastEventId=abc")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/thomaspoignant/go-feature-flag/internal"
+	"github.com/thomaspoignant/go-feature-flag/internal/metrics"
 	"github.com/thomaspoignant/go-feature-flag/internal/notifier"
 	"github.com/thomaspoignant/go-feature-flag/internal/retriever"
 )
@@ -30,9 +31,15 @@ type Config struct {
 	// Default: context.Background()
 	Context context.Context
 
-	// Retriever is the component in charge to retrieve your flag file
+	// Retriever is the component in charge to retrieve your flag file.
+	// You can use one of the retrievers of the library or implement your own Retriever.
 	Retriever Retriever
 
+	// Retrievers (optional) is a list of retrievers, use it if your flags are split in several files.
+	// The files are retrieved in parallel and merged, if a flag is defined in more than one file
+	// the last retriever of the list wins (Retriever is considered as the first of the list).
+	Retrievers []Retriever
+
 	// Notifiers (optional) is the list of notifiers called when a flag change
 	Notifiers []NotifierConfig
 
@@ -48,14 +55,66 @@ type Config struct {
 	// The init method will not return any error if the flag file is unreachable.
 	// Default: false
 	StartWithRetrieverError bool
+
+	// PersistentFlagConfigurationFile (optional) is the path of a local file where we save the last flags
+	// successfully retrieved. If the retriever fails at startup, the flags are loaded from this file.
+	// Default: no persistence
+	PersistentFlagConfigurationFile string
+
+	// Hooks (optional) are called around every evaluation of a flag, use them to add tracing, metrics
+	// or audit logs. Before is called in the order of the list, After, Error and Finally in the reverse
+	// order, and only for the hooks whose Before has been called.
+	Hooks []Hook
+
+	// Metrics (optional) receives the metrics of the library (evaluations, retrievals, exports ...),
+	// use ffmetrics.NewPrometheus to expose them in the Prometheus format.
+	// Default: no metrics
+	Metrics MetricsRecorder
+
+	// RelayProxy (optional) delegates the evaluation of the flags to a relay proxy, use it to avoid that every
+	// service retrieves the flags. In this mode the retrievers, notifiers and data exporter are not used,
+	// the relay proxy is in charge of it.
+	// Default: the flags are evaluated locally
+	RelayProxy *RelayProxy
 }
 
+// MetricsRecorder receives the metrics of go-feature-flag, see ffmetrics.Prometheus.
+type MetricsRecorder = metrics.Recorder
+
+// NoopMetricsRecorder is a MetricsRecorder doing nothing, embed it in your recorder to implement only
+// the methods you need.
+type NoopMetricsRecorder = metrics.Noop
+
+// UnknownFlagMetricsKey is the flag key recorded for the evaluations of the flags that are not in the cache.
+const UnknownFlagMetricsKey = metrics.UnknownFlag
+
 // GetRetriever returns a retriever.FlagRetriever configure with the retriever available in the config.
 func (c *Config) GetRetriever() (retriever.FlagRetriever, error) {
 	if c.Retriever == nil {
 		return nil, errors.New("no retriever in the configuration, impossible to get the flags")
 	}
-	return c.Retriever.getFlagRetriever()
+	// the retrievers of the library create a new internal retriever every time.
+	if provider, ok := c.Retriever.(flagRetrieverProvider); ok {
+		return provider.getFlagRetriever()
+	}
+	return c.Retriever, nil
+}
+
+// getRetrievers returns all the retrievers of the configuration, Retriever first.
+func (c *Config) getRetrievers() ([]Retriever, error) {
+	retrievers := make([]Retriever, 0, len(c.Retrievers)+1)
+	if c.Retriever != nil {
+		retrievers = append(retrievers, c.Retriever)
+	}
+	for _, r := range c.Retrievers {
+		if r != nil {
+			retrievers = append(retrievers, r)
+		}
+	}
+	if len(retrievers) == 0 {
+		return nil, errors.New("no retriever in the configuration, impossible to get the flags")
+	}
+	return retrievers, nil
 }
 
 // NotifierConfig is the interface for your notifiers.
@@ -72,7 +131,7 @@ func (c *Config) GetRetriever() (retriever.FlagRetriever, error) {
 //        // ...
 //    }
 type NotifierConfig interface {
-	GetNotifier(config Config) (notifier.Notifier, error)
+	GetNotifier(config Config) (Notifier, error)
 }
 
 // WebhookConfig is the configuration of your webhook.
@@ -132,7 +191,7 @@ type WebhookConfig struct {
 }
 
 // GetNotifier convert the configuration in a Notifier struct
-func (w *WebhookConfig) GetNotifier(config Config) (notifier.Notifier, error) {
+func (w *WebhookConfig) GetNotifier(config Config) (Notifier, error) {
 	url := w.EndpointURL
 
 	// remove this if when EndpointURL will be removed
@@ -152,7 +211,7 @@ type SlackNotifier struct {
 }
 
 // GetNotifier convert the configuration in a Notifier struct
-func (w *SlackNotifier) GetNotifier(config Config) (notifier.Notifier, error) {
+func (w *SlackNotifier) GetNotifier(config Config) (Notifier, error) {
 	notifier := notifier.NewSlackNotifier(config.Logger, internal.DefaultHTTPClient(), w.SlackWebhookURL)
 	return &notifier, nil
 }
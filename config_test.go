@@ -9,6 +9,7 @@ import (
 	"time"
 
 	ffClient "github.com/thomaspoignant/go-feature-flag"
+	"github.com/thomaspoignant/go-feature-flag/testutils"
 )
 
 func TestConfig_GetRetriever(t *testing.T) {
@@ -72,6 +73,24 @@ func TestConfig_GetRetriever(t *testing.T) {
 			want:    "*retriever.httpRetriever",
 			wantErr: false,
 		},
+		{
+			name: "Directory retriever",
+			fields: fields{
+				PollingInterval: 3 * time.Second,
+				Retriever:       &ffClient.DirectoryRetriever{Path: "testdata/flag-directory"},
+			},
+			want:    "*retriever.directoryRetriever",
+			wantErr: false,
+		},
+		{
+			name: "Custom retriever",
+			fields: fields{
+				PollingInterval: 3 * time.Second,
+				Retriever:       &testutils.MockRetriever{},
+			},
+			want:    "*testutils.MockRetriever",
+			wantErr: false,
+		},
 		{
 			name: "No retriever",
 			fields: fields{
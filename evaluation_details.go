@@ -0,0 +1,106 @@
+package ffclient
+
+import (
+	"fmt"
+
+	"github.com/thomaspoignant/go-feature-flag/internal/model"
+)
+
+// ResolutionReason explains why a value has been served to a user.
+type ResolutionReason = model.ResolutionReason
+
+const (
+	ReasonTargetingMatch      = model.ReasonTargetingMatch
+	ReasonTargetingKey        = model.ReasonTargetingKey
+	ReasonSplit               = model.ReasonSplit
+	ReasonStatic              = model.ReasonStatic
+	ReasonDefault             = model.ReasonDefault
+	ReasonDisabled            = model.ReasonDisabled
+	ReasonPrerequisiteFailed  = model.ReasonPrerequisiteFailed
+	ReasonExperimentationOver = model.ReasonExperimentationOver
+	ReasonFlagNotFound        = model.ReasonFlagNotFound
+	ReasonTypeMismatch        = model.ReasonTypeMismatch
+	ReasonError               = model.ReasonError
+)
+
+// ErrorCode describes the error that happened during an evaluation.
+type ErrorCode = model.ErrorCode
+
+const (
+	ErrorCodeFlagNotFound = model.ErrorCodeFlagNotFound
+	ErrorCodeTypeMismatch = model.ErrorCodeTypeMismatch
+	ErrorCodeGeneral      = model.ErrorCodeGeneral
+)
+
+// EvaluationDetails contains the information about how a flag has been evaluated for a user.
+type EvaluationDetails struct {
+	// Variation is the name of the variation served to the user.
+	// It can be True, False, Default, SdkDefault or the name of a variation of the flag.
+	Variation string `json:"variation"`
+
+	// Reason explains why this variation has been served.
+	Reason ResolutionReason `json:"reason"`
+
+	// ErrorCode is set when the evaluation failed and the SDK default value has been served.
+	ErrorCode ErrorCode `json:"errorCode,omitempty"`
+
+	// RuleName is the name of the targeting rule applied to the user, empty if no targeting rule applied.
+	RuleName string `json:"ruleName,omitempty"`
+}
+
+// BoolEvaluationDetails is the result of BoolVariationDetails.
+type BoolEvaluationDetails struct {
+	Value bool `json:"value"`
+	EvaluationDetails
+}
+
+// IntEvaluationDetails is the result of IntVariationDetails.
+type IntEvaluationDetails struct {
+	Value int `json:"value"`
+	EvaluationDetails
+}
+
+// Float64EvaluationDetails is the result of Float64VariationDetails.
+type Float64EvaluationDetails struct {
+	Value float64 `json:"value"`
+	EvaluationDetails
+}
+
+// StringEvaluationDetails is the result of StringVariationDetails.
+type StringEvaluationDetails struct {
+	Value string `json:"value"`
+	EvaluationDetails
+}
+
+// JSONArrayEvaluationDetails is the result of JSONArrayVariationDetails.
+type JSONArrayEvaluationDetails struct {
+	Value []interface{} `json:"value"`
+	EvaluationDetails
+}
+
+// JSONEvaluationDetails is the result of JSONVariationDetails.
+type JSONEvaluationDetails struct {
+	Value map[string]interface{} `json:"value"`
+	EvaluationDetails
+}
+
+// RawEvaluationDetails is the result of RawVariationDetails, the value is not converted.
+type RawEvaluationDetails struct {
+	Value interface{} `json:"value"`
+	EvaluationDetails
+}
+
+// SDKDefaultDetails returns the details of an evaluation serving the SDK default value,
+// the clients evaluating the flags remotely (ex: ffgrpc) use it to return the same details as the library.
+func SDKDefaultDetails(reason ResolutionReason, errorCode ErrorCode) EvaluationDetails {
+	return EvaluationDetails{
+		Variation: string(model.VariationSDKDefault),
+		Reason:    reason,
+		ErrorCode: errorCode,
+	}
+}
+
+// WrongVariationError returns the error of an evaluation when the value of the flag is not of the type expected.
+func WrongVariationError(flagKey string) error {
+	return fmt.Errorf("wrong variation used for flag %v", flagKey)
+}
@@ -1,7 +1,10 @@
 package ffclient
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"sync"
 	"time"
@@ -9,6 +12,8 @@ import (
 	"github.com/thomaspoignant/go-feature-flag/internal/cache"
 	"github.com/thomaspoignant/go-feature-flag/internal/exporter"
 	"github.com/thomaspoignant/go-feature-flag/internal/fflog"
+	"github.com/thomaspoignant/go-feature-flag/internal/metrics"
+	"github.com/thomaspoignant/go-feature-flag/internal/retriever"
 )
 
 // Init the feature flag component with the configuration of ffclient.Config
@@ -40,6 +45,21 @@ type GoFeatureFlag struct {
 	config       Config
 	bgUpdater    backgroundUpdater
 	dataExporter *exporter.DataExporterScheduler
+	watchers     []io.Closer
+
+	// remote is set when the flags are evaluated by a relay proxy.
+	remote *remoteEvaluator
+
+	// lastFiles are the flag files used for the last successful update of the cache.
+	lastFiles []cache.FlagFile
+	// lastValidators identify the version of the lastFiles, they are sent by the conditional retrievers
+	// to download the files only if they have changed.
+	lastValidators []retriever.Validators
+	// lastUpdateFailed is true if the last flag files retrieved were not loaded in the cache.
+	lastUpdateFailed bool
+
+	cacheStatus      CacheStatus
+	cacheStatusMutex sync.RWMutex
 }
 
 // ff is the default object for go-feature-flag
@@ -69,11 +89,23 @@ func New(config Config) (*GoFeatureFlag, error) {
 		// do nothing
 	}
 
+	if config.Context == nil {
+		config.Context = context.Background()
+	}
+
+	if config.RelayProxy != nil {
+		remote, err := newRemoteEvaluator(*config.RelayProxy)
+		if err != nil {
+			return nil, err
+		}
+		return &GoFeatureFlag{config: config, remote: remote}, nil
+	}
+
 	notifiers, err := getNotifiers(config)
 	if err != nil {
 		return nil, fmt.Errorf("wrong configuration in your webhook: %v", err)
 	}
-	notificationService := cache.NewNotificationService(notifiers)
+	notificationService := cache.NewNotificationServiceWithMetrics(notifiers, config.Metrics)
 
 	goFF := &GoFeatureFlag{
 		config:    config,
@@ -81,19 +113,45 @@ func New(config Config) (*GoFeatureFlag, error) {
 		cache:     cache.New(notificationService),
 	}
 
+	// init the retriever if it needs it
+	retrievers, _ := config.getRetrievers()
+	for _, r := range retrievers {
+		if r, ok := r.(InitializableRetriever); ok {
+			if err := r.Init(config.Context); err != nil {
+				return nil, fmt.Errorf("impossible to initialize the retriever: %v", err)
+			}
+		}
+	}
+
 	// fail if we cannot retrieve the flags the 1st time
-	err = retrieveFlagsAndUpdateCache(goFF.config, goFF.cache)
+	err = goFF.retrieveFlagsAndUpdateCache()
+	if err != nil && config.PersistentFlagConfigurationFile != "" {
+		// use the last flags saved if we cannot retrieve the flags
+		if errPersist := goFF.loadPersistentFile(); errPersist != nil {
+			fflog.Printf(config.Logger, "error: impossible to load the persistent flag file: %v\n", errPersist)
+		} else {
+			fflog.Printf(config.Logger, "warning: impossible to retrieve the flags, using the persistent flag file: %v\n", err)
+			err = nil
+		}
+	}
 	if err != nil && !config.StartWithRetrieverError {
 		return nil, fmt.Errorf("impossible to retrieve the flags, please check your configuration: %v", err)
 	}
 
+	// watch the flag files if the retrievers support it, polling is kept as a fallback
+	if err := goFF.startWatchers(retrievers); err != nil {
+		goFF.Close()
+		return nil, fmt.Errorf("impossible to watch the flag file: %v", err)
+	}
+
 	// start the flag update in background
 	go goFF.startFlagUpdaterDaemon()
 
 	if goFF.config.DataExporter.Exporter != nil {
 		// init the data exporter
-		goFF.dataExporter = exporter.NewDataExporterScheduler(goFF.config.Context, goFF.config.DataExporter.FlushInterval,
-			goFF.config.DataExporter.MaxEventInMemory, goFF.config.DataExporter.Exporter, goFF.config.Logger)
+		goFF.dataExporter = exporter.NewDataExporterSchedulerWithMetrics(goFF.config.Context,
+			goFF.config.DataExporter.FlushInterval, goFF.config.DataExporter.MaxEventInMemory,
+			goFF.config.DataExporter.Exporter, goFF.config.Logger, goFF.config.Metrics)
 
 		// we start the daemon only if we have a bulk exporter
 		if goFF.config.DataExporter.Exporter.IsBulk() {
@@ -106,6 +164,10 @@ func New(config Config) (*GoFeatureFlag, error) {
 // Close wait until thread are done
 func (g *GoFeatureFlag) Close() {
 	if g != nil {
+		for _, watcher := range g.watchers {
+			_ = watcher.Close()
+		}
+
 		if g.cache != nil {
 			// clear the cache
 			g.cache.Close()
@@ -115,6 +177,15 @@ func (g *GoFeatureFlag) Close() {
 		if g.dataExporter != nil {
 			g.dataExporter.Close()
 		}
+
+		retrievers, _ := g.config.getRetrievers()
+		for _, r := range retrievers {
+			if r, ok := r.(ShutdownableRetriever); ok {
+				if err := r.Shutdown(g.config.Context); err != nil {
+					fflog.Printf(g.config.Logger, "error while closing the retriever: %v\n", err)
+				}
+			}
+		}
 	}
 }
 
@@ -123,34 +194,185 @@ func (g *GoFeatureFlag) startFlagUpdaterDaemon() {
 	for {
 		select {
 		case <-g.bgUpdater.ticker.C:
-			err := retrieveFlagsAndUpdateCache(g.config, g.cache)
-			if err != nil {
-				fflog.Printf(g.config.Logger, "error while updating the cache: %v\n", err)
-			}
+			g.updateFlags()
+		case <-g.bgUpdater.triggerChan:
+			g.updateFlags()
 		case <-g.bgUpdater.updaterChan:
 			return
 		}
 	}
 }
 
+// updateFlags retrieves the flags and update the cache, errors are only logged.
+func (g *GoFeatureFlag) updateFlags() {
+	err := g.retrieveFlagsAndUpdateCache()
+	if err != nil {
+		fflog.Printf(g.config.Logger, "error while updating the cache: %v\n", err)
+	}
+}
+
+// startWatchers starts to watch the changes of the flag files for the retrievers supporting it,
+// every change triggers an update of the flags.
+func (g *GoFeatureFlag) startWatchers(retrievers []Retriever) error {
+	for _, r := range retrievers {
+		if r, ok := r.(watchableRetriever); ok {
+			watcher, err := r.startWatching(g.bgUpdater.trigger)
+			if err != nil {
+				return err
+			}
+			if watcher != nil {
+				g.watchers = append(g.watchers, watcher)
+			}
+		}
+	}
+	return nil
+}
+
 // retrieveFlagsAndUpdateCache is called every X seconds to refresh the cache flag.
-func retrieveFlagsAndUpdateCache(config Config, cache cache.Cache) error {
-	retriever, err := config.GetRetriever()
+// If none of the flag files has been modified since the last call, the cache is not updated.
+// The retrieval is recorded as failed if the cache has not been updated with the files.
+func (g *GoFeatureFlag) retrieveFlagsAndUpdateCache() (err error) {
+	start := time.Now()
+	defer func() {
+		g.metricsRecorder().RecordRetrieval(time.Since(start), err)
+	}()
+
+	retrievers, err := g.config.getRetrievers()
 	if err != nil {
 		log.Printf("error while getting the file retriever: %v", err)
 		return err
 	}
 
-	loadedFlags, err := retriever.Retrieve(config.Context)
+	files, validators, modified, err := retrieveFlagFiles(g.config, retrievers, g.lastFiles, g.lastValidators)
 	if err != nil {
 		log.Printf("error: impossible to retrieve flags from the config file: %v", err)
 		return err
 	}
+	now := time.Now()
+	if !modified {
+		if g.lastUpdateFailed {
+			// the cache is still using older flags than the files, it is not up to date.
+			return errors.New("the flag files have not changed since the last update of the cache that failed")
+		}
+		g.setCacheStatus(CacheStatus{LastUpdate: now})
+		g.recordCacheRefresh(now)
+		return nil
+	}
 
-	err = cache.UpdateCache(loadedFlags, config.FileFormat)
+	conflicts, err := g.cache.UpdateCacheFromFiles(files)
 	if err != nil {
 		log.Printf("error: impossible to update the cache of the flags: %v", err)
+		// the validators are not kept, the next retrieval is compared with the last files loaded.
+		g.lastUpdateFailed = true
+		return err
+	}
+	g.lastFiles = files
+	g.lastValidators = validators
+	g.lastUpdateFailed = false
+	for _, conflict := range conflicts {
+		fflog.Printf(g.config.Logger, "warning: conflict between the flag files, %s\n", conflict)
+	}
+	g.setCacheStatus(CacheStatus{LastUpdate: now})
+	g.recordCacheRefresh(now)
+
+	if g.config.PersistentFlagConfigurationFile != "" {
+		if err := cache.SavePersistentFile(g.config.PersistentFlagConfigurationFile, files, now); err != nil {
+			fflog.Printf(g.config.Logger, "error: impossible to save the persistent flag file: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// metricsRecorder returns the recorder of the configuration, a noop recorder if there is none.
+func (g *GoFeatureFlag) metricsRecorder() metrics.Recorder {
+	return metrics.OrNoop(g.config.Metrics)
+}
+
+// recordCacheRefresh records the number of flags in the cache after a successful retrieval.
+func (g *GoFeatureFlag) recordCacheRefresh(refreshedAt time.Time) {
+	flags, err := g.cache.AllFlags()
+	if err != nil {
+		return
+	}
+	g.metricsRecorder().RecordCacheRefresh(len(flags), refreshedAt)
+}
+
+// loadPersistentFile updates the cache with the flags saved in the persistent flag file.
+func (g *GoFeatureFlag) loadPersistentFile() error {
+	files, updatedAt, err := cache.LoadPersistentFile(g.config.PersistentFlagConfigurationFile)
+	if err != nil {
 		return err
 	}
+	if _, err := g.cache.UpdateCacheFromFiles(files); err != nil {
+		return err
+	}
+	g.setCacheStatus(CacheStatus{LastUpdate: updatedAt, FromPersistentFile: true})
 	return nil
 }
+
+// retrieveFlagFiles calls all the retrievers in parallel.
+// The conditional retrievers send the validators of the previous files, if a retriever returns ErrNotModified
+// the file of the previous call is used.
+// modified is false if none of the files has changed since the previous call.
+// If one of the retrievers returns an error, no file is returned.
+func retrieveFlagFiles(config Config, retrievers []Retriever, previous []cache.FlagFile,
+	previousValidators []retriever.Validators) (
+	files []cache.FlagFile, validators []retriever.Validators, modified bool, err error) {
+	files = make([]cache.FlagFile, len(retrievers))
+	validators = make([]retriever.Validators, len(retrievers))
+	errs := make([]error, len(retrievers))
+	notModified := make([]bool, len(retrievers))
+
+	wg := sync.WaitGroup{}
+	for i, r := range retrievers {
+		wg.Add(1)
+		go func(i int, r Retriever) {
+			defer wg.Done()
+			source := fmt.Sprintf("retriever #%d (%T)", i+1, r)
+			// without a previous file, the full file is downloaded.
+			var previousVersion retriever.Validators
+			if i < len(previous) && i < len(previousValidators) {
+				previousVersion = previousValidators[i]
+			}
+			content, version, err := retrieveFile(config.Context, r, previousVersion)
+			if errors.Is(err, ErrNotModified) && i < len(previous) {
+				files[i] = previous[i]
+				validators[i] = previousVersion
+				notModified[i] = true
+				return
+			}
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %v", source, err)
+				return
+			}
+			files[i] = cache.FlagFile{
+				Source:     source,
+				Content:    content,
+				FileFormat: getFileFormat(config, r),
+			}
+			validators[i] = version
+		}(i, r)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if !notModified[i] {
+			modified = true
+		}
+	}
+	return files, validators, modified, nil
+}
+
+// retrieveFile calls the retriever, a conditional retriever downloads the file only if it has changed
+// since the version identified by the validators.
+func retrieveFile(ctx context.Context, r Retriever,
+	validators retriever.Validators) ([]byte, retriever.Validators, error) {
+	if r, ok := r.(conditionalRetriever); ok {
+		return r.retrieveIfModified(ctx, validators)
+	}
+	content, err := r.Retrieve(ctx)
+	return content, retriever.Validators{}, err
+}
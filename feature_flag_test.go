@@ -1,12 +1,19 @@
 package ffclient_test
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/stretchr/testify/assert"
 	ffclient "github.com/thomaspoignant/go-feature-flag"
 	"io/ioutil"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -182,6 +189,43 @@ func TestUpdateFlag(t *testing.T) {
 	assert.False(t, flagValue)
 }
 
+func TestUpdateFlagWatchFile(t *testing.T) {
+	initialFileContent := `test-flag:
+  rule: key eq "random-key"
+  percentage: 100
+  true: true
+  false: false
+  default: false`
+
+	flagFile, _ := ioutil.TempFile("", "")
+	defer os.Remove(flagFile.Name())
+	_ = ioutil.WriteFile(flagFile.Name(), []byte(initialFileContent), 0600)
+
+	gffClient1, err := ffclient.New(ffclient.Config{
+		PollingInterval: 60 * time.Second,
+		Retriever:       &ffclient.FileRetriever{Path: flagFile.Name(), Watch: true},
+		Logger:          log.New(os.Stdout, "", 0),
+	})
+	assert.NoError(t, err)
+	defer gffClient1.Close()
+
+	flagValue, _ := gffClient1.BoolVariation("test-flag", ffuser.NewUser("random-key"), false)
+	assert.True(t, flagValue)
+
+	updatedFileContent := `test-flag:
+  rule: key eq "random-key2"
+  percentage: 100
+  true: true
+  false: false
+  default: false`
+
+	_ = ioutil.WriteFile(flagFile.Name(), []byte(updatedFileContent), 0600)
+	time.Sleep(500 * time.Millisecond)
+
+	flagValue, _ = gffClient1.BoolVariation("test-flag", ffuser.NewUser("random-key"), false)
+	assert.False(t, flagValue, "the flags should be updated without waiting for the polling interval")
+}
+
 func TestImpossibleToLoadfile(t *testing.T) {
 	initialFileContent := `test-flag:
   rule: key eq "random-key"
@@ -281,3 +325,316 @@ func TestValidUseCaseBigFlagFile(t *testing.T) {
 	hasUnknownFlag, _ := gff.BoolVariation("unknown-flag", user, false)
 	assert.False(t, hasUnknownFlag, "User should use default value if flag does not exists")
 }
+
+func TestCustomRetriever(t *testing.T) {
+	r := &testutils.MockRetriever{Content: []byte(`test-flag:
+  true: "true"
+  false: "false"
+  default: "default"
+  percentage: 100
+`)}
+	gff, err := ffclient.New(ffclient.Config{
+		PollingInterval: 1 * time.Second,
+		Retriever:       r,
+	})
+	assert.NoError(t, err)
+	assert.True(t, r.InitCalled, "Init should be called before the first retrieve")
+
+	flagValue, _ := gff.StringVariation("test-flag", ffuser.NewUser("random-key"), "SDKdefault")
+	assert.Equal(t, "true", flagValue)
+
+	r.SetContent([]byte(`test-flag:
+  true: "new-true"
+  false: "false"
+  default: "default"
+  percentage: 100
+`))
+	time.Sleep(1500 * time.Millisecond)
+	flagValue, _ = gff.StringVariation("test-flag", ffuser.NewUser("random-key"), "SDKdefault")
+	assert.Equal(t, "new-true", flagValue, "the flags should be refreshed with the retriever")
+
+	gff.Close()
+	assert.True(t, r.ShutdownCalled, "Shutdown should be called when closing go-feature-flag")
+}
+
+func TestCustomRetrieverInitError(t *testing.T) {
+	r := &testutils.MockRetriever{InitErr: errors.New("init error")}
+	_, err := ffclient.New(ffclient.Config{
+		Retriever: r,
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 0, r.RetrieveCalls, "Retrieve should not be called if Init fails")
+}
+
+func TestCustomRetrieverError(t *testing.T) {
+	r := &testutils.MockRetriever{Err: errors.New("retrieve error")}
+	_, err := ffclient.New(ffclient.Config{
+		Retriever: r,
+	})
+	assert.Error(t, err)
+}
+
+func TestMultipleRetrievers(t *testing.T) {
+	platform := &testutils.MockRetriever{Content: []byte(`shared-flag:
+  true: "platform"
+  false: "false"
+  default: "default"
+  percentage: 100
+
+platform-flag:
+  true: "platform"
+  false: "false"
+  default: "default"
+  percentage: 100
+`)}
+	team := &testutils.MockRetriever{
+		FileFormat: "json",
+		Content: []byte(`{
+  "shared-flag": {"true": "team", "false": "false", "default": "default", "percentage": 100}
+}`),
+	}
+
+	gff, err := ffclient.New(ffclient.Config{
+		Retriever:  platform,
+		Retrievers: []ffclient.Retriever{team},
+	})
+	assert.NoError(t, err)
+	defer gff.Close()
+	assert.True(t, platform.InitCalled)
+	assert.True(t, team.InitCalled)
+
+	user := ffuser.NewUser("random-key")
+	flagValue, _ := gff.StringVariation("shared-flag", user, "SDKdefault")
+	assert.Equal(t, "team", flagValue, "the last retriever should win")
+	flagValue, _ = gff.StringVariation("platform-flag", user, "SDKdefault")
+	assert.Equal(t, "platform", flagValue)
+}
+
+func TestMultipleRetrieversOneError(t *testing.T) {
+	_, err := ffclient.New(ffclient.Config{
+		Retrievers: []ffclient.Retriever{
+			&ffclient.FileRetriever{Path: "testdata/flag-config.yaml"},
+			&testutils.MockRetriever{Err: errors.New("retrieve error")},
+		},
+	})
+	assert.Error(t, err)
+}
+
+func TestRetrieverNotModified(t *testing.T) {
+	notModified := &testutils.MockRetriever{Content: []byte(`flag-a:
+  true: "a"
+  false: "false"
+  default: "default"
+  percentage: 100
+`)}
+	modified := &testutils.MockRetriever{Content: []byte(`flag-b:
+  true: "b"
+  false: "false"
+  default: "default"
+  percentage: 100
+`)}
+	gff, err := ffclient.New(ffclient.Config{
+		PollingInterval: 1 * time.Second,
+		Retrievers:      []ffclient.Retriever{notModified, modified},
+	})
+	assert.NoError(t, err)
+	defer gff.Close()
+
+	notModified.SetErr(ffclient.ErrNotModified)
+	modified.SetContent([]byte(`flag-b:
+  true: "new-b"
+  false: "false"
+  default: "default"
+  percentage: 100
+`))
+	time.Sleep(1500 * time.Millisecond)
+
+	user := ffuser.NewUser("random-key")
+	flagValue, _ := gff.StringVariation("flag-a", user, "SDKdefault")
+	assert.Equal(t, "a", flagValue, "the previous file should be used if not modified")
+	flagValue, _ = gff.StringVariation("flag-b", user, "SDKdefault")
+	assert.Equal(t, "new-b", flagValue)
+}
+
+func TestValidUseCaseDirectory(t *testing.T) {
+	gff, err := ffclient.New(ffclient.Config{
+		PollingInterval: 5 * time.Second,
+		Retriever:       &ffclient.DirectoryRetriever{Path: "testdata/flag-directory"},
+	})
+	assert.NoError(t, err)
+	defer gff.Close()
+
+	user := ffuser.NewUser("random-key")
+	hasTeamAFlag, _ := gff.BoolVariation("team-a-flag", user, false)
+	assert.True(t, hasTeamAFlag)
+	teamBFlag, _ := gff.StringVariation("team-b-flag", user, "SDKdefault")
+	assert.Equal(t, "beta", teamBFlag, "segments should be loaded from the other files")
+	teamCFlag, _ := gff.IntVariation("team-c-flag", user, -1)
+	assert.Equal(t, 3, teamCFlag)
+}
+
+func TestDirectoryDuplicatedFlag(t *testing.T) {
+	_, err := ffclient.New(ffclient.Config{
+		Retriever: &ffclient.DirectoryRetriever{Path: "testdata/flag-directory-duplicate"},
+	})
+	assert.Error(t, err)
+}
+
+func TestPersistentFlagConfigurationFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "persistent")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	persistentFile := filepath.Join(dir, "flags.json")
+
+	gff, err := ffclient.New(ffclient.Config{
+		Retriever:                       &ffclient.FileRetriever{Path: "testdata/flag-config.yaml"},
+		PersistentFlagConfigurationFile: persistentFile,
+	})
+	assert.NoError(t, err)
+	status := gff.GetCacheStatus()
+	assert.False(t, status.FromPersistentFile)
+	assert.False(t, status.LastUpdate.IsZero())
+	gff.Close()
+
+	// the retriever is unreachable at startup, the persistent file should be used
+	gff, err = ffclient.New(ffclient.Config{
+		Retriever:                       &ffclient.FileRetriever{Path: "testdata/not-a-file.yaml"},
+		PersistentFlagConfigurationFile: persistentFile,
+	})
+	assert.NoError(t, err)
+	defer gff.Close()
+
+	hasTestFlag, _ := gff.BoolVariation("test-flag", ffuser.NewUser("random-key"), false)
+	assert.True(t, hasTestFlag, "User should have test flag from the persistent file")
+
+	persistentStatus := gff.GetCacheStatus()
+	assert.True(t, persistentStatus.FromPersistentFile)
+	assert.True(t, status.LastUpdate.Equal(persistentStatus.LastUpdate),
+		"the last update should be the date of the flags in the persistent file")
+	assert.True(t, persistentStatus.Age() > 0)
+}
+
+func TestPersistentFlagConfigurationFileMissing(t *testing.T) {
+	_, err := ffclient.New(ffclient.Config{
+		Retriever:                       &ffclient.FileRetriever{Path: "testdata/not-a-file.yaml"},
+		PersistentFlagConfigurationFile: "testdata/not-a-persistent-file.json",
+	})
+	assert.Error(t, err)
+}
+
+func TestRetrieverNotModifiedAfterInvalidFile(t *testing.T) {
+	valid := "test-flag:\n  true: true\n  false: false\n  default: false\n  percentage: 100\n"
+	invalid := "test-flag:\n  rule: key eq\n  true: true\n  false: false\n  default: false\n"
+	var mutex sync.Mutex
+	content, conditionalRequests := valid, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		etag := `"` + fmt.Sprint(len(content)) + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			conditionalRequests++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write([]byte(content))
+	}))
+	defer srv.Close()
+
+	gff, err := ffclient.New(ffclient.Config{
+		PollingInterval: 1 * time.Second,
+		Logger:          log.New(ioutil.Discard, "", 0),
+		Retriever:       &ffclient.HTTPRetriever{URL: srv.URL},
+	})
+	assert.NoError(t, err)
+	defer gff.Close()
+	loadedAt := gff.GetCacheStatus().LastUpdate
+
+	mutex.Lock()
+	content = invalid
+	mutex.Unlock()
+	time.Sleep(2500 * time.Millisecond)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.Equal(t, 0, conditionalRequests, "the invalid file should be downloaded again at every polling")
+	assert.Equal(t, loadedAt, gff.GetCacheStatus().LastUpdate, "the cache is not up to date")
+	flagValue, _ := gff.BoolVariation("test-flag", ffuser.NewUser("random-key"), false)
+	assert.True(t, flagValue, "the last valid flags should be kept")
+}
+
+// versionedFlagServer serves a flag file with an ETag, the value of test-flag changes with the version.
+type versionedFlagServer struct {
+	*httptest.Server
+	mutex   sync.Mutex
+	version int
+}
+
+func newVersionedFlagServer() *versionedFlagServer {
+	s := &versionedFlagServer{version: 1}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		etag := fmt.Sprintf(`"v%d"`, s.version)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		_, _ = fmt.Fprintf(w, "test-flag:\n  true: \"v%d\"\n  false: \"false\"\n  default: \"false\"\n  percentage: 100\n",
+			s.version)
+	}))
+	return s
+}
+
+func (s *versionedFlagServer) setVersion(version int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.version = version
+}
+
+func TestRetrieverNotModifiedAfterFailedRetrieval(t *testing.T) {
+	srv := newVersionedFlagServer()
+	defer srv.Close()
+	other := &testutils.MockRetriever{Content: []byte("other-flag:\n  true: true\n  false: false\n  default: false\n")}
+
+	gff, err := ffclient.New(ffclient.Config{
+		PollingInterval: 1 * time.Second,
+		Logger:          log.New(ioutil.Discard, "", 0),
+		Retrievers:      []ffclient.Retriever{&ffclient.HTTPRetriever{URL: srv.URL}, other},
+	})
+	assert.NoError(t, err)
+	defer gff.Close()
+
+	// the new version is downloaded while the other retriever fails, the cache is not updated.
+	srv.setVersion(2)
+	other.SetErr(errors.New("unavailable"))
+	time.Sleep(1500 * time.Millisecond)
+	flagValue, _ := gff.StringVariation("test-flag", ffuser.NewUser("random-key"), "SDKdefault")
+	assert.Equal(t, "v1", flagValue)
+
+	other.SetErr(nil)
+	time.Sleep(1 * time.Second)
+	flagValue, _ = gff.StringVariation("test-flag", ffuser.NewUser("random-key"), "SDKdefault")
+	assert.Equal(t, "v2", flagValue, "the new version should be loaded once all the retrievers succeed")
+}
+
+func TestRetrieverNotModifiedSharedRetriever(t *testing.T) {
+	srv := newVersionedFlagServer()
+	defer srv.Close()
+	shared := &ffclient.HTTPRetriever{URL: srv.URL}
+
+	gff1, err := ffclient.New(ffclient.Config{PollingInterval: 1 * time.Second, Retriever: shared})
+	assert.NoError(t, err)
+	defer gff1.Close()
+	gff2, err := ffclient.New(ffclient.Config{PollingInterval: 1 * time.Second, Retriever: shared})
+	assert.NoError(t, err, "the validators of the other instance should not be used")
+	defer gff2.Close()
+
+	srv.setVersion(2)
+	time.Sleep(1500 * time.Millisecond)
+	for _, gff := range []*ffclient.GoFeatureFlag{gff1, gff2} {
+		flagValue, _ := gff.StringVariation("test-flag", ffuser.NewUser("random-key"), "SDKdefault")
+		assert.Equal(t, "v2", flagValue)
+	}
+}
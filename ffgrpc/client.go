@@ -0,0 +1,302 @@
+package ffgrpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+	"github.com/thomaspoignant/go-feature-flag/ffuser"
+)
+
+// DefaultTimeout is the maximum time of an evaluation if the context has no deadline.
+const DefaultTimeout = time.Second
+
+// Client evaluates the flags with the gRPC service of a relay proxy.
+// If the relay proxy is not available, the default value is returned with an error like
+// in ffclient when a flag is not available.
+type Client struct {
+	// Timeout is the maximum time of an evaluation if the context has no deadline.
+	// Default: DefaultTimeout
+	Timeout time.Duration
+
+	service EvaluationServiceClient
+	conn    *grpc.ClientConn
+}
+
+// NewClient creates a client using an existing gRPC connection, the connection is not closed by Close.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{Timeout: DefaultTimeout, service: NewEvaluationServiceClient(conn)}
+}
+
+// Dial creates a client connected to the relay proxy, the connection is closed by Close.
+func Dial(target string, opts ...grpc.DialOption) (*Client, error) {
+	conn, err := grpc.Dial(target, opts...)
+	if err != nil {
+		return nil, err
+	}
+	client := NewClient(conn)
+	client.conn = conn
+	return client, nil
+}
+
+// Close closes the connection if it has been opened by Dial.
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// BoolVariation return the value of the flag in boolean.
+func (c *Client) BoolVariation(flagKey string, user ffuser.User, defaultValue bool) (bool, error) {
+	return c.BoolVariationCtx(context.Background(), flagKey, user, defaultValue)
+}
+
+// IntVariation return the value of the flag in int.
+func (c *Client) IntVariation(flagKey string, user ffuser.User, defaultValue int) (int, error) {
+	return c.IntVariationCtx(context.Background(), flagKey, user, defaultValue)
+}
+
+// Float64Variation return the value of the flag in float64.
+func (c *Client) Float64Variation(flagKey string, user ffuser.User, defaultValue float64) (float64, error) {
+	return c.Float64VariationCtx(context.Background(), flagKey, user, defaultValue)
+}
+
+// StringVariation return the value of the flag in string.
+func (c *Client) StringVariation(flagKey string, user ffuser.User, defaultValue string) (string, error) {
+	return c.StringVariationCtx(context.Background(), flagKey, user, defaultValue)
+}
+
+// JSONArrayVariation return the value of the flag in []interface{}.
+func (c *Client) JSONArrayVariation(
+	flagKey string, user ffuser.User, defaultValue []interface{}) ([]interface{}, error) {
+	return c.JSONArrayVariationCtx(context.Background(), flagKey, user, defaultValue)
+}
+
+// JSONVariation return the value of the flag in map[string]interface{}.
+func (c *Client) JSONVariation(
+	flagKey string, user ffuser.User, defaultValue map[string]interface{}) (map[string]interface{}, error) {
+	return c.JSONVariationCtx(context.Background(), flagKey, user, defaultValue)
+}
+
+// BoolVariationDetails return the value of the flag in boolean with the details of the evaluation.
+func (c *Client) BoolVariationDetails(
+	flagKey string, user ffuser.User, defaultValue bool) (ffclient.BoolEvaluationDetails, error) {
+	return c.BoolVariationDetailsCtx(context.Background(), flagKey, user, defaultValue)
+}
+
+// IntVariationDetails return the value of the flag in int with the details of the evaluation.
+func (c *Client) IntVariationDetails(
+	flagKey string, user ffuser.User, defaultValue int) (ffclient.IntEvaluationDetails, error) {
+	return c.IntVariationDetailsCtx(context.Background(), flagKey, user, defaultValue)
+}
+
+// Float64VariationDetails return the value of the flag in float64 with the details of the evaluation.
+func (c *Client) Float64VariationDetails(
+	flagKey string, user ffuser.User, defaultValue float64) (ffclient.Float64EvaluationDetails, error) {
+	return c.Float64VariationDetailsCtx(context.Background(), flagKey, user, defaultValue)
+}
+
+// StringVariationDetails return the value of the flag in string with the details of the evaluation.
+func (c *Client) StringVariationDetails(
+	flagKey string, user ffuser.User, defaultValue string) (ffclient.StringEvaluationDetails, error) {
+	return c.StringVariationDetailsCtx(context.Background(), flagKey, user, defaultValue)
+}
+
+// JSONArrayVariationDetails return the value of the flag in []interface{} with the details of the evaluation.
+func (c *Client) JSONArrayVariationDetails(
+	flagKey string, user ffuser.User, defaultValue []interface{}) (ffclient.JSONArrayEvaluationDetails, error) {
+	return c.JSONArrayVariationDetailsCtx(context.Background(), flagKey, user, defaultValue)
+}
+
+// JSONVariationDetails return the value of the flag in map[string]interface{} with the details of the evaluation.
+func (c *Client) JSONVariationDetails(
+	flagKey string, user ffuser.User, defaultValue map[string]interface{}) (ffclient.JSONEvaluationDetails, error) {
+	return c.JSONVariationDetailsCtx(context.Background(), flagKey, user, defaultValue)
+}
+
+// BoolVariationCtx return the value of the flag in boolean.
+func (c *Client) BoolVariationCtx(
+	ctx context.Context, flagKey string, user ffuser.User, defaultValue bool) (bool, error) {
+	res, err := c.BoolVariationDetailsCtx(ctx, flagKey, user, defaultValue)
+	return res.Value, err
+}
+
+// IntVariationCtx return the value of the flag in int.
+func (c *Client) IntVariationCtx(
+	ctx context.Context, flagKey string, user ffuser.User, defaultValue int) (int, error) {
+	res, err := c.IntVariationDetailsCtx(ctx, flagKey, user, defaultValue)
+	return res.Value, err
+}
+
+// Float64VariationCtx return the value of the flag in float64.
+func (c *Client) Float64VariationCtx(
+	ctx context.Context, flagKey string, user ffuser.User, defaultValue float64) (float64, error) {
+	res, err := c.Float64VariationDetailsCtx(ctx, flagKey, user, defaultValue)
+	return res.Value, err
+}
+
+// StringVariationCtx return the value of the flag in string.
+func (c *Client) StringVariationCtx(
+	ctx context.Context, flagKey string, user ffuser.User, defaultValue string) (string, error) {
+	res, err := c.StringVariationDetailsCtx(ctx, flagKey, user, defaultValue)
+	return res.Value, err
+}
+
+// JSONArrayVariationCtx return the value of the flag in []interface{}.
+func (c *Client) JSONArrayVariationCtx(
+	ctx context.Context, flagKey string, user ffuser.User, defaultValue []interface{}) ([]interface{}, error) {
+	res, err := c.JSONArrayVariationDetailsCtx(ctx, flagKey, user, defaultValue)
+	return res.Value, err
+}
+
+// JSONVariationCtx return the value of the flag in map[string]interface{}.
+func (c *Client) JSONVariationCtx(ctx context.Context, flagKey string, user ffuser.User,
+	defaultValue map[string]interface{}) (map[string]interface{}, error) {
+	res, err := c.JSONVariationDetailsCtx(ctx, flagKey, user, defaultValue)
+	return res.Value, err
+}
+
+// BoolVariationDetailsCtx return the value of the flag in boolean with the details of the evaluation.
+func (c *Client) BoolVariationDetailsCtx(
+	ctx context.Context, flagKey string, user ffuser.User, defaultValue bool) (ffclient.BoolEvaluationDetails, error) {
+	value, details, err := c.evaluate(ctx, flagKey, user, defaultValue, func(v interface{}) (interface{}, bool) {
+		res, ok := v.(bool)
+		return res, ok
+	})
+	return ffclient.BoolEvaluationDetails{Value: value.(bool), EvaluationDetails: details}, err
+}
+
+// IntVariationDetailsCtx return the value of the flag in int with the details of the evaluation.
+func (c *Client) IntVariationDetailsCtx(
+	ctx context.Context, flagKey string, user ffuser.User, defaultValue int) (ffclient.IntEvaluationDetails, error) {
+	value, details, err := c.evaluate(ctx, flagKey, user, defaultValue, func(v interface{}) (interface{}, bool) {
+		// the numbers are always float64 in protobuf
+		res, ok := v.(float64)
+		return int(res), ok
+	})
+	return ffclient.IntEvaluationDetails{Value: value.(int), EvaluationDetails: details}, err
+}
+
+// Float64VariationDetailsCtx return the value of the flag in float64 with the details of the evaluation.
+func (c *Client) Float64VariationDetailsCtx(ctx context.Context, flagKey string, user ffuser.User,
+	defaultValue float64) (ffclient.Float64EvaluationDetails, error) {
+	value, details, err := c.evaluate(ctx, flagKey, user, defaultValue, func(v interface{}) (interface{}, bool) {
+		res, ok := v.(float64)
+		return res, ok
+	})
+	return ffclient.Float64EvaluationDetails{Value: value.(float64), EvaluationDetails: details}, err
+}
+
+// StringVariationDetailsCtx return the value of the flag in string with the details of the evaluation.
+func (c *Client) StringVariationDetailsCtx(ctx context.Context, flagKey string, user ffuser.User,
+	defaultValue string) (ffclient.StringEvaluationDetails, error) {
+	value, details, err := c.evaluate(ctx, flagKey, user, defaultValue, func(v interface{}) (interface{}, bool) {
+		res, ok := v.(string)
+		return res, ok
+	})
+	return ffclient.StringEvaluationDetails{Value: value.(string), EvaluationDetails: details}, err
+}
+
+// JSONArrayVariationDetailsCtx return the value of the flag in []interface{} with the details of the evaluation.
+func (c *Client) JSONArrayVariationDetailsCtx(ctx context.Context, flagKey string, user ffuser.User,
+	defaultValue []interface{}) (ffclient.JSONArrayEvaluationDetails, error) {
+	value, details, err := c.evaluate(ctx, flagKey, user, defaultValue, func(v interface{}) (interface{}, bool) {
+		res, ok := v.([]interface{})
+		return res, ok
+	})
+	return ffclient.JSONArrayEvaluationDetails{Value: value.([]interface{}), EvaluationDetails: details}, err
+}
+
+// JSONVariationDetailsCtx return the value of the flag in map[string]interface{} with the details of the evaluation.
+func (c *Client) JSONVariationDetailsCtx(ctx context.Context, flagKey string, user ffuser.User,
+	defaultValue map[string]interface{}) (ffclient.JSONEvaluationDetails, error) {
+	value, details, err := c.evaluate(ctx, flagKey, user, defaultValue, func(v interface{}) (interface{}, bool) {
+		res, ok := v.(map[string]interface{})
+		return res, ok
+	})
+	return ffclient.JSONEvaluationDetails{Value: value.(map[string]interface{}), EvaluationDetails: details}, err
+}
+
+// AllFlagsState return the evaluation of all the flags for a user.
+// An invalid AllFlags is return if the relay proxy is not available.
+func (c *Client) AllFlagsState(user ffuser.User) ffclient.AllFlags {
+	invalid := ffclient.AllFlags{Flags: make(map[string]ffclient.FlagState), Valid: false}
+	protoUser, err := UserToProto(user)
+	if err != nil {
+		return invalid
+	}
+	ctx, cancel := c.withTimeout(context.Background())
+	defer cancel()
+	resp, err := c.service.EvaluateAllFlags(ctx, &EvaluateAllFlagsRequest{User: protoUser})
+	if err != nil {
+		return invalid
+	}
+
+	res := ffclient.AllFlags{Flags: make(map[string]ffclient.FlagState, len(resp.GetFlags())), Valid: resp.GetValid()}
+	for key, state := range resp.GetFlags() {
+		res.Flags[key] = ffclient.FlagState{
+			Value:       state.GetValue().AsInterface(),
+			Variation:   state.GetVariation(),
+			Reason:      ffclient.ResolutionReason(state.GetReason()),
+			RuleName:    state.GetRuleName(),
+			TrackEvents: state.GetTrackEvents(),
+			ClientSide:  state.GetClientSide(),
+		}
+	}
+	return res
+}
+
+// evaluate asks the relay proxy to evaluate the flag and converts the value with the convert function.
+// If something goes wrong we return the sdkDefault value and the details of the error.
+func (c *Client) evaluate(ctx context.Context, flagKey string, user ffuser.User, sdkDefault interface{},
+	convert func(interface{}) (interface{}, bool)) (interface{}, ffclient.EvaluationDetails, error) {
+	protoUser, err := UserToProto(user)
+	if err != nil {
+		return sdkDefault, ffclient.SDKDefaultDetails(ffclient.ReasonError, ffclient.ErrorCodeGeneral), err
+	}
+	protoDefault, err := structpb.NewValue(sdkDefault)
+	if err != nil {
+		return sdkDefault, ffclient.SDKDefaultDetails(ffclient.ReasonError, ffclient.ErrorCodeGeneral), err
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	resp, err := c.service.EvaluateFlag(ctx, &EvaluateFlagRequest{
+		FlagKey:      flagKey,
+		User:         protoUser,
+		DefaultValue: protoDefault,
+	})
+	if err != nil {
+		return sdkDefault, ffclient.SDKDefaultDetails(ffclient.ReasonError, ffclient.ErrorCodeGeneral),
+			fmt.Errorf("impossible to evaluate the flag %v: %v", flagKey, err)
+	}
+
+	details := ffclient.EvaluationDetails{
+		Variation: resp.GetVariation(),
+		Reason:    ffclient.ResolutionReason(resp.GetReason()),
+		ErrorCode: ffclient.ErrorCode(resp.GetErrorCode()),
+		RuleName:  resp.GetRuleName(),
+	}
+	if resp.GetFailed() {
+		return sdkDefault, details, fmt.Errorf("%s", resp.GetErrorMessage())
+	}
+	res, ok := convert(resp.GetValue().AsInterface())
+	if !ok {
+		return sdkDefault, ffclient.SDKDefaultDetails(ffclient.ReasonTypeMismatch, ffclient.ErrorCodeTypeMismatch),
+			ffclient.WrongVariationError(flagKey)
+	}
+	return res, details, nil
+}
+
+// withTimeout adds the timeout of the client to the context if it has no deadline.
+func (c *Client) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || c.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.Timeout)
+}
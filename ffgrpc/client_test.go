@@ -0,0 +1,192 @@
+package ffgrpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+	"github.com/thomaspoignant/go-feature-flag/ffgrpc"
+	"github.com/thomaspoignant/go-feature-flag/ffuser"
+)
+
+// evaluator contains the variation methods shared by ffclient.GoFeatureFlag and ffgrpc.Client.
+type evaluator interface {
+	BoolVariation(flagKey string, user ffuser.User, defaultValue bool) (bool, error)
+	IntVariationCtx(ctx context.Context, flagKey string, user ffuser.User, defaultValue int) (int, error)
+	StringVariationDetails(flagKey string, user ffuser.User, defaultValue string) (ffclient.StringEvaluationDetails, error)
+	JSONVariationDetailsCtx(ctx context.Context, flagKey string, user ffuser.User,
+		defaultValue map[string]interface{}) (ffclient.JSONEvaluationDetails, error)
+	AllFlagsState(user ffuser.User) ffclient.AllFlags
+}
+
+var (
+	_ evaluator = &ffclient.GoFeatureFlag{}
+	_ evaluator = &ffgrpc.Client{}
+)
+
+// serviceMock returns the value of the flag set in the mock for every evaluation.
+// The evaluation of slow-flag never answers, it ends when the client gives up.
+type serviceMock struct {
+	ffgrpc.UnimplementedEvaluationServiceServer
+	value    interface{}
+	lastUser *ffgrpc.User
+}
+
+func (s *serviceMock) EvaluateFlag(
+	ctx context.Context, req *ffgrpc.EvaluateFlagRequest) (*ffgrpc.EvaluateFlagResponse, error) {
+	if req.GetFlagKey() == "slow-flag" {
+		// the mock is not read, the request can still be running when the next tests change it.
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	s.lastUser = req.GetUser()
+	if req.GetFlagKey() == "unknown-flag" {
+		return &ffgrpc.EvaluateFlagResponse{
+			Value:        req.GetDefaultValue(),
+			Variation:    "SdkDefault",
+			Reason:       "FLAG_NOT_FOUND",
+			ErrorCode:    "FLAG_NOT_FOUND",
+			Failed:       true,
+			ErrorMessage: "flag unknown-flag is not present or disabled",
+		}, nil
+	}
+	value, err := structpb.NewValue(s.value)
+	if err != nil {
+		return nil, err
+	}
+	return &ffgrpc.EvaluateFlagResponse{Value: value, Variation: "True", Reason: "TARGETING_MATCH"}, nil
+}
+
+func (s *serviceMock) EvaluateAllFlags(
+	_ context.Context, _ *ffgrpc.EvaluateAllFlagsRequest) (*ffgrpc.EvaluateAllFlagsResponse, error) {
+	return &ffgrpc.EvaluateAllFlagsResponse{
+		Valid: true,
+		Flags: map[string]*ffgrpc.FlagState{
+			"test-flag": {Value: structpb.NewBoolValue(true), Variation: "True", Reason: "STATIC", ClientSide: true},
+		},
+	}, nil
+}
+
+func newTestClient(t *testing.T, service *serviceMock) (*ffgrpc.Client, func()) {
+	listener := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	ffgrpc.RegisterEvaluationServiceServer(srv, service)
+	go func() { _ = srv.Serve(listener) }()
+
+	client, err := ffgrpc.Dial("bufnet", grpc.WithInsecure(),
+		grpc.WithContextDialer(func(_ context.Context, _ string) (net.Conn, error) {
+			return listener.Dial()
+		}))
+	assert.NoError(t, err)
+	return client, func() {
+		_ = client.Close()
+		srv.Stop()
+	}
+}
+
+func TestClient(t *testing.T) {
+	service := &serviceMock{}
+	client, stop := newTestClient(t, service)
+	defer stop()
+	user := ffuser.NewUserBuilder("random-key").AddCustom("age", 32).Build()
+
+	t.Run("Bool", func(t *testing.T) {
+		service.value = true
+		got, err := client.BoolVariation("test-flag", user, false)
+		assert.NoError(t, err)
+		assert.True(t, got)
+		assert.Equal(t, "random-key", service.lastUser.GetKey())
+		assert.Equal(t, int64(32), service.lastUser.GetCustom()["age"].GetIntValue())
+	})
+
+	t.Run("Int", func(t *testing.T) {
+		service.value = 12
+		got, err := client.IntVariation("test-flag", user, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, 12, got)
+	})
+
+	t.Run("Float64", func(t *testing.T) {
+		service.value = 12.5
+		got, err := client.Float64Variation("test-flag", user, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, 12.5, got)
+	})
+
+	t.Run("String with details", func(t *testing.T) {
+		service.value = "value"
+		got, err := client.StringVariationDetails("test-flag", user, "default")
+		assert.NoError(t, err)
+		assert.Equal(t, ffclient.StringEvaluationDetails{
+			Value: "value",
+			EvaluationDetails: ffclient.EvaluationDetails{
+				Variation: "True",
+				Reason:    ffclient.ReasonTargetingMatch,
+			},
+		}, got)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		service.value = map[string]interface{}{"color": "blue", "sizes": []interface{}{"S", "M"}}
+		got, err := client.JSONVariation("test-flag", user, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"color": "blue", "sizes": []interface{}{"S", "M"}}, got)
+
+		gotArray, err := client.JSONArrayVariation("test-flag", user, []interface{}{})
+		assert.Error(t, err)
+		assert.Equal(t, []interface{}{}, gotArray)
+	})
+
+	t.Run("Type mismatch", func(t *testing.T) {
+		service.value = "value"
+		got, err := client.BoolVariationDetails("test-flag", user, true)
+		assert.EqualError(t, err, "wrong variation used for flag test-flag")
+		assert.True(t, got.Value)
+		assert.Equal(t, ffclient.ReasonTypeMismatch, got.Reason)
+	})
+
+	t.Run("Flag not found", func(t *testing.T) {
+		got, err := client.StringVariationDetails("unknown-flag", user, "default")
+		assert.EqualError(t, err, "flag unknown-flag is not present or disabled")
+		assert.Equal(t, "default", got.Value)
+		assert.Equal(t, ffclient.ErrorCodeFlagNotFound, got.ErrorCode)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		client.Timeout = 10 * time.Millisecond
+		defer func() { client.Timeout = ffgrpc.DefaultTimeout }()
+
+		got, err := client.BoolVariationDetails("slow-flag", user, false)
+		assert.Error(t, err)
+		assert.False(t, got.Value)
+		assert.Equal(t, ffclient.ReasonError, got.Reason)
+		assert.Equal(t, ffclient.ErrorCodeGeneral, got.ErrorCode)
+	})
+
+	t.Run("All flags", func(t *testing.T) {
+		got := client.AllFlagsState(user)
+		assert.True(t, got.Valid)
+		assert.Equal(t, map[string]ffclient.FlagState{
+			"test-flag": {Value: true, Variation: "True", Reason: ffclient.ReasonStatic, ClientSide: true},
+		}, got.Flags)
+		assert.Len(t, got.ClientSideOnly().Flags, 1)
+	})
+}
+
+func TestClient_unavailable(t *testing.T) {
+	client, stop := newTestClient(t, &serviceMock{})
+	stop()
+	user := ffuser.NewUser("random-key")
+
+	got, err := client.IntVariation("test-flag", user, 42)
+	assert.Error(t, err)
+	assert.Equal(t, 42, got)
+	assert.False(t, client.AllFlagsState(user).Valid)
+}
@@ -0,0 +1,209 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package ffgrpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// EvaluationServiceClient is the client API for EvaluationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type EvaluationServiceClient interface {
+	// EvaluateFlag evaluates one flag for the user.
+	EvaluateFlag(ctx context.Context, in *EvaluateFlagRequest, opts ...grpc.CallOption) (*EvaluateFlagResponse, error)
+	// EvaluateAllFlags evaluates all the flags for the user.
+	EvaluateAllFlags(ctx context.Context, in *EvaluateAllFlagsRequest, opts ...grpc.CallOption) (*EvaluateAllFlagsResponse, error)
+	// WatchFlags streams the changes of the flags, the first event is a snapshot of all the flags
+	// unless last_event_id is set and the changes after it are still known.
+	WatchFlags(ctx context.Context, in *WatchFlagsRequest, opts ...grpc.CallOption) (EvaluationService_WatchFlagsClient, error)
+}
+
+type evaluationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEvaluationServiceClient(cc grpc.ClientConnInterface) EvaluationServiceClient {
+	return &evaluationServiceClient{cc}
+}
+
+func (c *evaluationServiceClient) EvaluateFlag(ctx context.Context, in *EvaluateFlagRequest, opts ...grpc.CallOption) (*EvaluateFlagResponse, error) {
+	out := new(EvaluateFlagResponse)
+	err := c.cc.Invoke(ctx, "/gofeatureflag.v1.EvaluationService/EvaluateFlag", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *evaluationServiceClient) EvaluateAllFlags(ctx context.Context, in *EvaluateAllFlagsRequest, opts ...grpc.CallOption) (*EvaluateAllFlagsResponse, error) {
+	out := new(EvaluateAllFlagsResponse)
+	err := c.cc.Invoke(ctx, "/gofeatureflag.v1.EvaluationService/EvaluateAllFlags", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *evaluationServiceClient) WatchFlags(ctx context.Context, in *WatchFlagsRequest, opts ...grpc.CallOption) (EvaluationService_WatchFlagsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &EvaluationService_ServiceDesc.Streams[0], "/gofeatureflag.v1.EvaluationService/WatchFlags", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &evaluationServiceWatchFlagsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type EvaluationService_WatchFlagsClient interface {
+	Recv() (*FlagEvent, error)
+	grpc.ClientStream
+}
+
+type evaluationServiceWatchFlagsClient struct {
+	grpc.ClientStream
+}
+
+func (x *evaluationServiceWatchFlagsClient) Recv() (*FlagEvent, error) {
+	m := new(FlagEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// EvaluationServiceServer is the server API for EvaluationService service.
+// All implementations must embed UnimplementedEvaluationServiceServer
+// for forward compatibility
+type EvaluationServiceServer interface {
+	// EvaluateFlag evaluates one flag for the user.
+	EvaluateFlag(context.Context, *EvaluateFlagRequest) (*EvaluateFlagResponse, error)
+	// EvaluateAllFlags evaluates all the flags for the user.
+	EvaluateAllFlags(context.Context, *EvaluateAllFlagsRequest) (*EvaluateAllFlagsResponse, error)
+	// WatchFlags streams the changes of the flags, the first event is a snapshot of all the flags
+	// unless last_event_id is set and the changes after it are still known.
+	WatchFlags(*WatchFlagsRequest, EvaluationService_WatchFlagsServer) error
+	mustEmbedUnimplementedEvaluationServiceServer()
+}
+
+// UnimplementedEvaluationServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedEvaluationServiceServer struct {
+}
+
+func (UnimplementedEvaluationServiceServer) EvaluateFlag(context.Context, *EvaluateFlagRequest) (*EvaluateFlagResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EvaluateFlag not implemented")
+}
+func (UnimplementedEvaluationServiceServer) EvaluateAllFlags(context.Context, *EvaluateAllFlagsRequest) (*EvaluateAllFlagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EvaluateAllFlags not implemented")
+}
+func (UnimplementedEvaluationServiceServer) WatchFlags(*WatchFlagsRequest, EvaluationService_WatchFlagsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchFlags not implemented")
+}
+func (UnimplementedEvaluationServiceServer) mustEmbedUnimplementedEvaluationServiceServer() {}
+
+// UnsafeEvaluationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EvaluationServiceServer will
+// result in compilation errors.
+type UnsafeEvaluationServiceServer interface {
+	mustEmbedUnimplementedEvaluationServiceServer()
+}
+
+func RegisterEvaluationServiceServer(s grpc.ServiceRegistrar, srv EvaluationServiceServer) {
+	s.RegisterService(&EvaluationService_ServiceDesc, srv)
+}
+
+func _EvaluationService_EvaluateFlag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EvaluateFlagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EvaluationServiceServer).EvaluateFlag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gofeatureflag.v1.EvaluationService/EvaluateFlag",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EvaluationServiceServer).EvaluateFlag(ctx, req.(*EvaluateFlagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EvaluationService_EvaluateAllFlags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EvaluateAllFlagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EvaluationServiceServer).EvaluateAllFlags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gofeatureflag.v1.EvaluationService/EvaluateAllFlags",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EvaluationServiceServer).EvaluateAllFlags(ctx, req.(*EvaluateAllFlagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EvaluationService_WatchFlags_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchFlagsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EvaluationServiceServer).WatchFlags(m, &evaluationServiceWatchFlagsServer{stream})
+}
+
+type EvaluationService_WatchFlagsServer interface {
+	Send(*FlagEvent) error
+	grpc.ServerStream
+}
+
+type evaluationServiceWatchFlagsServer struct {
+	grpc.ServerStream
+}
+
+func (x *evaluationServiceWatchFlagsServer) Send(m *FlagEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// EvaluationService_ServiceDesc is the grpc.ServiceDesc for EvaluationService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EvaluationService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gofeatureflag.v1.EvaluationService",
+	HandlerType: (*EvaluationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "EvaluateFlag",
+			Handler:    _EvaluationService_EvaluateFlag_Handler,
+		},
+		{
+			MethodName: "EvaluateAllFlags",
+			Handler:    _EvaluationService_EvaluateAllFlags_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchFlags",
+			Handler:       _EvaluationService_WatchFlags_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "ffgrpc/evaluation.proto",
+}
@@ -0,0 +1,13 @@
+module github.com/thomaspoignant/go-feature-flag/ffgrpc
+
+go 1.15
+
+require (
+	github.com/stretchr/testify v1.7.0
+	github.com/thomaspoignant/go-feature-flag v0.0.0-00010101000000-000000000000
+	google.golang.org/grpc v1.38.0
+	google.golang.org/protobuf v1.26.0
+)
+
+// the module is released with go-feature-flag, it always uses the version of the same commit.
+replace github.com/thomaspoignant/go-feature-flag => ../
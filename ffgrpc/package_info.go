@@ -0,0 +1,14 @@
+// Package ffgrpc contains the gRPC evaluation service of the relay proxy and a client to use it.
+//
+// The Client has the same variation methods as ffclient.GoFeatureFlag, so you can switch from
+// an embedded evaluation to a remote evaluation without changing your code.
+//
+//	client, err := ffgrpc.Dial("relay-proxy:1032", grpc.WithInsecure())
+//	defer client.Close()
+//
+//	user := ffuser.NewUser("user-unique-key")
+//	hasFlag, _ := client.BoolVariation("test-flag", user, false)
+//
+// ffgrpc is a separate module, the library does not depend on gRPC if you don't use it.
+// The Go code of the service is generated from evaluation.proto with make proto.
+package ffgrpc
@@ -0,0 +1,185 @@
+package ffgrpc
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/thomaspoignant/go-feature-flag/ffuser"
+)
+
+// UserToProto converts a user in its protobuf representation.
+// The custom attributes can be nil, strings, numbers, booleans, lists or maps with string keys (JSON values),
+// an error is returned for the other types.
+func UserToProto(user ffuser.User) (*User, error) {
+	custom := make(map[string]*AttributeValue, len(user.GetCustom()))
+	for name, value := range user.GetCustom() {
+		attr, err := attributeToProto(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid custom attribute %s: %v", name, err)
+		}
+		custom[name] = attr
+	}
+	return &User{Key: user.GetKey(), Anonymous: user.IsAnonymous(), Custom: custom}, nil
+}
+
+// UserFromProto converts the protobuf representation of a user in a ffuser.User.
+func UserFromProto(user *User) (ffuser.User, error) {
+	if user == nil {
+		return ffuser.User{}, fmt.Errorf("no user")
+	}
+	builder := ffuser.NewUserBuilder(user.GetKey()).Anonymous(user.GetAnonymous())
+	for name, attr := range user.GetCustom() {
+		value, err := attributeFromProto(attr)
+		if err != nil {
+			return ffuser.User{}, fmt.Errorf("invalid custom attribute %s: %v", name, err)
+		}
+		builder = builder.AddCustom(name, value)
+	}
+	return builder.Build(), nil
+}
+
+// attributeToProto converts a custom attribute, the integers and the floats are kept separated
+// to be able to read them back with the same type. The maps are sent as JSON objects.
+// The uint64 bigger than the max int64 are sent as floats like in JSON.
+func attributeToProto(value interface{}) (*AttributeValue, error) {
+	v, isNil := indirect(reflect.ValueOf(value))
+	if isNil {
+		return &AttributeValue{Kind: &AttributeValue_NullValue{NullValue: structpb.NullValue_NULL_VALUE}}, nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return &AttributeValue{Kind: &AttributeValue_StringValue{StringValue: v.String()}}, nil
+	case reflect.Bool:
+		return &AttributeValue{Kind: &AttributeValue_BoolValue{BoolValue: v.Bool()}}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &AttributeValue{Kind: &AttributeValue_IntValue{IntValue: v.Int()}}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() > math.MaxInt64 {
+			return &AttributeValue{Kind: &AttributeValue_DoubleValue{DoubleValue: float64(v.Uint())}}, nil
+		}
+		return &AttributeValue{Kind: &AttributeValue_IntValue{IntValue: int64(v.Uint())}}, nil
+	case reflect.Float32, reflect.Float64:
+		return &AttributeValue{Kind: &AttributeValue_DoubleValue{DoubleValue: v.Float()}}, nil
+	case reflect.Slice, reflect.Array:
+		values := make([]*AttributeValue, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			attr, err := attributeToProto(v.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, attr)
+		}
+		return &AttributeValue{Kind: &AttributeValue_ListValue{ListValue: &AttributeList{Values: values}}}, nil
+	case reflect.Map:
+		m, err := jsonValue(v)
+		if err != nil {
+			return nil, err
+		}
+		mapValue, err := structpb.NewStruct(m.(map[string]interface{}))
+		if err != nil {
+			return nil, err
+		}
+		return &AttributeValue{Kind: &AttributeValue_MapValue{MapValue: mapValue}}, nil
+	default:
+		return nil, fmt.Errorf("type %s is not supported", v.Type())
+	}
+}
+
+// jsonValue converts a value in the types supported by structpb (the types of a JSON value).
+func jsonValue(value reflect.Value) (interface{}, error) {
+	v, isNil := indirect(value)
+	if isNil {
+		return nil, nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.Slice, reflect.Array:
+		values := make([]interface{}, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			item, err := jsonValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, item)
+		}
+		return values, nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("type %s is not supported, the keys of a map should be strings", v.Type())
+		}
+		values := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			item, err := jsonValue(iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			values[iter.Key().String()] = item
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("type %s is not supported", v.Type())
+	}
+}
+
+// indirect returns the value pointed by the pointers and the interfaces, isNil is true for a nil value
+// (nil pointer, nil interface, nil map or nil slice like in JSON).
+func indirect(v reflect.Value) (value reflect.Value, isNil bool) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return v, true
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Invalid:
+		return v, true
+	case reflect.Map, reflect.Slice:
+		return v, v.IsNil()
+	default:
+		return v, false
+	}
+}
+
+// attributeFromProto converts a custom attribute in a Go value, the integers are read as int,
+// the floats as float64, the lists as []interface{} and the maps as map[string]interface{}.
+func attributeFromProto(attr *AttributeValue) (interface{}, error) {
+	switch kind := attr.GetKind().(type) {
+	case *AttributeValue_StringValue:
+		return kind.StringValue, nil
+	case *AttributeValue_BoolValue:
+		return kind.BoolValue, nil
+	case *AttributeValue_IntValue:
+		return int(kind.IntValue), nil
+	case *AttributeValue_DoubleValue:
+		return kind.DoubleValue, nil
+	case *AttributeValue_ListValue:
+		values := make([]interface{}, 0, len(kind.ListValue.GetValues()))
+		for _, item := range kind.ListValue.GetValues() {
+			value, err := attributeFromProto(item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	case *AttributeValue_NullValue:
+		return nil, nil
+	case *AttributeValue_MapValue:
+		return kind.MapValue.AsMap(), nil
+	default:
+		return nil, fmt.Errorf("empty value")
+	}
+}
@@ -0,0 +1,99 @@
+package ffgrpc_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thomaspoignant/go-feature-flag/ffgrpc"
+	"github.com/thomaspoignant/go-feature-flag/ffuser"
+)
+
+func TestUserToProto(t *testing.T) {
+	user := ffuser.NewUserBuilder("random-key").
+		Anonymous(true).
+		AddCustom("email", "john.doe@example.com").
+		AddCustom("age", 32).
+		AddCustom("score", 12.5).
+		AddCustom("beta", true).
+		AddCustom("teams", []interface{}{"team-a", 2, false}).
+		AddCustom("roles", []string{"admin"}).
+		AddCustom("id", uint64(math.MaxUint64)).
+		AddCustom("count", uint64(3)).
+		AddCustom("company", nil).
+		AddCustom("address", map[string]interface{}{
+			"city":    "Paris",
+			"zip":     75001,
+			"geo":     map[string]float64{"lat": 48.86},
+			"tags":    []string{"home"},
+			"country": nil,
+		}).
+		Build()
+
+	protoUser, err := ffgrpc.UserToProto(user)
+	assert.NoError(t, err)
+	got, err := ffgrpc.UserFromProto(protoUser)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "random-key", got.GetKey())
+	assert.True(t, got.IsAnonymous())
+	assert.Equal(t, map[string]interface{}{
+		"email":   "john.doe@example.com",
+		"age":     32,
+		"score":   12.5,
+		"beta":    true,
+		"teams":   []interface{}{"team-a", 2, false},
+		"roles":   []interface{}{"admin"},
+		"id":      float64(math.MaxUint64),
+		"count":   3,
+		"company": nil,
+		"address": map[string]interface{}{
+			"city":    "Paris",
+			"zip":     float64(75001),
+			"geo":     map[string]interface{}{"lat": 48.86},
+			"tags":    []interface{}{"home"},
+			"country": nil,
+		},
+	}, got.GetCustom())
+}
+
+func TestUserToProto_unsupportedType(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		wantErr string
+	}{
+		{
+			name:    "Channel",
+			value:   make(chan int),
+			wantErr: "invalid custom attribute attr: type chan int is not supported",
+		},
+		{
+			name:  "Map without string keys",
+			value: map[int]string{1: "a"},
+			wantErr: "invalid custom attribute attr: type map[int]string is not supported, " +
+				"the keys of a map should be strings",
+		},
+		{
+			name:    "Function in a map",
+			value:   map[string]interface{}{"callback": func() {}},
+			wantErr: "invalid custom attribute attr: type func() is not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := ffuser.NewUserBuilder("random-key").AddCustom("attr", tt.value).Build()
+			_, err := ffgrpc.UserToProto(user)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestUserFromProto_invalid(t *testing.T) {
+	_, err := ffgrpc.UserFromProto(nil)
+	assert.Error(t, err)
+
+	_, err = ffgrpc.UserFromProto(&ffgrpc.User{Key: "random-key", Custom: map[string]*ffgrpc.AttributeValue{"empty": {}}})
+	assert.EqualError(t, err, "invalid custom attribute empty: empty value")
+}
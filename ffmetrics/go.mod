@@ -0,0 +1,12 @@
+module github.com/thomaspoignant/go-feature-flag/ffmetrics
+
+go 1.15
+
+require (
+	github.com/prometheus/client_golang v1.11.0
+	github.com/stretchr/testify v1.7.0
+	github.com/thomaspoignant/go-feature-flag v0.0.0-00010101000000-000000000000
+)
+
+// the module is released with go-feature-flag, it always uses the version of the same commit.
+replace github.com/thomaspoignant/go-feature-flag => ../
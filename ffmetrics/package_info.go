@@ -0,0 +1,9 @@
+// Package ffmetrics exposes the metrics of go-feature-flag in the Prometheus format.
+//
+//	metrics, _ := ffmetrics.NewPrometheus(nil)
+//	ffclient.Init(ffclient.Config{
+//	  //...
+//	  Metrics: metrics,
+//	})
+//	http.Handle("/metrics", metrics.Handler())
+package ffmetrics
@@ -0,0 +1,169 @@
+package ffmetrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace is the prefix of all the metrics.
+const namespace = "goff"
+
+// Prometheus records the metrics of go-feature-flag in Prometheus collectors, use it in ffclient.Config.Metrics.
+//
+// Metrics:
+//   - goff_flag_evaluations_total{flag, variation, reason}: number of evaluations, the flags that are not
+//     in the cache are recorded with the flag "<unknown>" (ffclient.UnknownFlagMetricsKey).
+//   - goff_retrievals_total{result}: number of retrievals of the flags, result is success or failure.
+//   - goff_retrieval_duration_seconds: duration of the retrievals.
+//   - goff_cache_flags: number of flags in the cache.
+//   - goff_cache_last_refresh_timestamp_seconds: date of the last successful retrieval.
+//   - goff_export_batch_size: number of events sent to the data exporter in one export.
+//   - goff_export_duration_seconds: duration of the exports.
+//   - goff_export_failures_total: number of failed exports.
+//   - goff_export_dropped_events_total: number of events dropped without being exported.
+//   - goff_notifier_failures_total{notifier}: number of notifications that failed.
+type Prometheus struct {
+	gatherer prometheus.Gatherer
+
+	evaluations       *prometheus.CounterVec
+	retrievals        *prometheus.CounterVec
+	retrievalDuration prometheus.Histogram
+	cacheFlags        prometheus.Gauge
+	lastRefresh       prometheus.Gauge
+	exportBatchSize   prometheus.Histogram
+	exportDuration    prometheus.Histogram
+	exportFailures    prometheus.Counter
+	droppedEvents     prometheus.Counter
+	notifierFailures  *prometheus.CounterVec
+}
+
+// NewPrometheus creates the collectors and registers them in registerer.
+// If registerer is nil, a new registry is created and Handler serves only the metrics of go-feature-flag.
+func NewPrometheus(registerer prometheus.Registerer) (*Prometheus, error) {
+	var gatherer prometheus.Gatherer
+	switch r := registerer.(type) {
+	case nil:
+		registry := prometheus.NewRegistry()
+		registerer, gatherer = registry, registry
+	case prometheus.Gatherer:
+		gatherer = r
+	default:
+		gatherer = prometheus.DefaultGatherer
+	}
+
+	p := &Prometheus{
+		gatherer: gatherer,
+		evaluations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "flag_evaluations_total",
+			Help:      "Number of evaluations of the flags.",
+		}, []string{"flag", "variation", "reason"}),
+		retrievals: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "retrievals_total",
+			Help:      "Number of retrievals of the flags.",
+		}, []string{"result"}),
+		retrievalDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "retrieval_duration_seconds",
+			Help:      "Duration of the retrievals of the flags.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		cacheFlags: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cache_flags",
+			Help:      "Number of flags in the cache.",
+		}),
+		lastRefresh: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cache_last_refresh_timestamp_seconds",
+			Help:      "Date of the last successful retrieval of the flags.",
+		}),
+		exportBatchSize: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "export_batch_size",
+			Help:      "Number of events sent to the data exporter in one export.",
+			Buckets:   prometheus.ExponentialBuckets(1, 10, 6),
+		}),
+		exportDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "export_duration_seconds",
+			Help:      "Duration of the exports of the events.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		exportFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "export_failures_total",
+			Help:      "Number of exports of events that failed.",
+		}),
+		droppedEvents: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "export_dropped_events_total",
+			Help:      "Number of events dropped without being exported.",
+		}),
+		notifierFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "notifier_failures_total",
+			Help:      "Number of notifications of flag changes that failed.",
+		}, []string{"notifier"}),
+	}
+
+	collectors := []prometheus.Collector{
+		p.evaluations, p.retrievals, p.retrievalDuration, p.cacheFlags, p.lastRefresh,
+		p.exportBatchSize, p.exportDuration, p.exportFailures, p.droppedEvents, p.notifierFailures,
+	}
+	for _, c := range collectors {
+		if err := registerer.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (p *Prometheus) Handler() http.Handler {
+	return promhttp.HandlerFor(p.gatherer, promhttp.HandlerOpts{})
+}
+
+// RecordEvaluation counts the evaluation of a flag.
+func (p *Prometheus) RecordEvaluation(flagKey string, variation string, reason string) {
+	p.evaluations.WithLabelValues(flagKey, variation, reason).Inc()
+}
+
+// RecordRetrieval counts the retrieval of the flags and observes its duration.
+func (p *Prometheus) RecordRetrieval(duration time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	p.retrievals.WithLabelValues(result).Inc()
+	p.retrievalDuration.Observe(duration.Seconds())
+}
+
+// RecordCacheRefresh sets the number of flags and the date of the last successful retrieval.
+func (p *Prometheus) RecordCacheRefresh(flagCount int, refreshedAt time.Time) {
+	p.cacheFlags.Set(float64(flagCount))
+	p.lastRefresh.Set(float64(refreshedAt.UnixNano()) / float64(time.Second))
+}
+
+// RecordExport observes the size and the duration of an export and counts the failures.
+func (p *Prometheus) RecordExport(batchSize int, duration time.Duration, err error) {
+	p.exportBatchSize.Observe(float64(batchSize))
+	p.exportDuration.Observe(duration.Seconds())
+	if err != nil {
+		p.exportFailures.Inc()
+	}
+}
+
+// RecordDroppedEvents counts the events dropped by the data exporter.
+func (p *Prometheus) RecordDroppedEvents(count int) {
+	p.droppedEvents.Add(float64(count))
+}
+
+// RecordNotifierFailure counts the failure of a notifier.
+func (p *Prometheus) RecordNotifierFailure(notifier string) {
+	p.notifierFailures.WithLabelValues(notifier).Inc()
+}
@@ -0,0 +1,70 @@
+package ffmetrics_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+	"github.com/thomaspoignant/go-feature-flag/ffmetrics"
+)
+
+var _ ffclient.MetricsRecorder = &ffmetrics.Prometheus{}
+
+func scrape(t *testing.T, p *ffmetrics.Prometheus) string {
+	rec := httptest.NewRecorder()
+	p.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := ioutil.ReadAll(rec.Body)
+	assert.NoError(t, err)
+	return string(body)
+}
+
+func TestPrometheus(t *testing.T) {
+	p, err := ffmetrics.NewPrometheus(nil)
+	assert.NoError(t, err)
+
+	p.RecordEvaluation("test-flag", "True", "TARGETING_MATCH")
+	p.RecordEvaluation("test-flag", "True", "TARGETING_MATCH")
+	p.RecordRetrieval(200*time.Millisecond, nil)
+	p.RecordRetrieval(time.Second, errors.New("timeout"))
+	p.RecordCacheRefresh(3, time.Unix(1600000000, 0))
+	p.RecordExport(10, 50*time.Millisecond, nil)
+	p.RecordExport(5, 50*time.Millisecond, errors.New("s3 unavailable"))
+	p.RecordDroppedEvents(5)
+	p.RecordNotifierFailure("WebhookNotifier")
+
+	got := scrape(t, p)
+	want := []string{
+		`goff_flag_evaluations_total{flag="test-flag",reason="TARGETING_MATCH",variation="True"} 2`,
+		`goff_retrievals_total{result="success"} 1`,
+		`goff_retrievals_total{result="failure"} 1`,
+		`goff_retrieval_duration_seconds_count 2`,
+		`goff_cache_flags 3`,
+		`goff_cache_last_refresh_timestamp_seconds 1.6e+09`,
+		`goff_export_batch_size_sum 15`,
+		`goff_export_duration_seconds_count 2`,
+		`goff_export_failures_total 1`,
+		`goff_export_dropped_events_total 5`,
+		`goff_notifier_failures_total{notifier="WebhookNotifier"} 1`,
+	}
+	for _, line := range want {
+		assert.Contains(t, got, line)
+	}
+}
+
+func TestNewPrometheus_registerer(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	p, err := ffmetrics.NewPrometheus(registry)
+	assert.NoError(t, err)
+	p.RecordNotifierFailure("SlackNotifier")
+	assert.Contains(t, scrape(t, p), `goff_notifier_failures_total{notifier="SlackNotifier"} 1`)
+
+	// the collectors are already registered in this registry.
+	_, err = ffmetrics.NewPrometheus(registry)
+	assert.Error(t, err)
+}
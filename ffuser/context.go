@@ -0,0 +1,19 @@
+package ffuser
+
+import "context"
+
+// contextKey is the type of the key used to store a user in a context,
+// it is unexported to avoid collisions with the keys defined in other packages.
+type contextKey struct{}
+
+// NewContext returns a copy of the context carrying the user.
+func NewContext(ctx context.Context, user User) context.Context {
+	return context.WithValue(ctx, contextKey{}, user)
+}
+
+// FromContext returns the user stored in the context by NewContext,
+// false is returned if the context does not carry a user.
+func FromContext(ctx context.Context) (User, bool) {
+	user, ok := ctx.Value(contextKey{}).(User)
+	return user, ok
+}
@@ -0,0 +1,22 @@
+package ffuser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewContext(t *testing.T) {
+	user := NewUserBuilder("random-key").AddCustom("email", "john@doe.com").Build()
+	ctx := NewContext(context.Background(), user)
+
+	got, ok := FromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, user, got)
+}
+
+func TestFromContext_noUser(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+}
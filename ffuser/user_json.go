@@ -0,0 +1,29 @@
+package ffuser
+
+import "encoding/json"
+
+// jsonUser is the JSON representation of a User.
+type jsonUser struct {
+	Key       string                 `json:"key"`
+	Anonymous bool                   `json:"anonymous"`
+	Custom    map[string]interface{} `json:"custom,omitempty"`
+}
+
+// MarshalJSON writes the user in JSON:
+//   {"key": "user-key", "anonymous": false, "custom": {"email": "john.doe@example.com"}}
+func (u User) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonUser{Key: u.key, Anonymous: u.anonymous, Custom: u.custom})
+}
+
+// UnmarshalJSON reads a user written with MarshalJSON, it is used to receive users from other services.
+func (u *User) UnmarshalJSON(data []byte) error {
+	var res jsonUser
+	if err := json.Unmarshal(data, &res); err != nil {
+		return err
+	}
+	if res.Custom == nil {
+		res.Custom = map[string]interface{}{}
+	}
+	*u = User{key: res.Key, anonymous: res.Anonymous, custom: res.Custom}
+	return nil
+}
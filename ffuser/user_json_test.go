@@ -0,0 +1,26 @@
+package ffuser
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUser_JSON(t *testing.T) {
+	user := NewUserBuilder("random-key").Anonymous(true).AddCustom("email", "john.doe@example.com").Build()
+	content, err := json.Marshal(user)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"key": "random-key", "anonymous": true, "custom": {"email": "john.doe@example.com"}}`,
+		string(content))
+
+	var got User
+	assert.NoError(t, json.Unmarshal(content, &got))
+	assert.Equal(t, user, got)
+
+	var withoutCustom User
+	assert.NoError(t, json.Unmarshal([]byte(`{"key": "random-key"}`), &withoutCustom))
+	assert.Equal(t, NewUser("random-key"), withoutCustom)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"key": 12}`), &withoutCustom))
+}
@@ -0,0 +1,93 @@
+package ffclient
+
+import (
+	"context"
+
+	"github.com/thomaspoignant/go-feature-flag/ffuser"
+)
+
+// Hook is called around every evaluation of a flag, use it to add tracing, metrics or audit logs
+// to your evaluations. Hooks are added in Config.Hooks.
+//
+// For each evaluation, Before is called first, then After if the evaluation succeeds or Error if it fails,
+// and Finally at the end in all cases.
+// Before is called in the order of the list, After, Error and Finally in the reverse order (like in OpenFeature).
+// If Before fails, only the hooks whose Before has been called receive Error and Finally.
+// Embed NoopHook in your hook if you don't need all the methods.
+type Hook interface {
+	// Before is called before the evaluation, the user returned is used for the evaluation
+	// and for the next hooks, so you can enrich it (ex: with attributes from the context).
+	// If an error is returned, the flag is not evaluated and the default value is served.
+	Before(ctx context.Context, flagKey string, user ffuser.User) (ffuser.User, error)
+
+	// After is called when the flag has been evaluated successfully.
+	After(ctx context.Context, flagKey string, user ffuser.User, result RawEvaluationDetails)
+
+	// Error is called when the evaluation failed, the default value has been served.
+	Error(ctx context.Context, flagKey string, user ffuser.User, err error)
+
+	// Finally is called at the end of every evaluation.
+	Finally(ctx context.Context, flagKey string, user ffuser.User)
+}
+
+// NoopHook is a Hook doing nothing, embed it in your hook to implement only the methods you need.
+type NoopHook struct{}
+
+// Before returns the user unchanged.
+func (NoopHook) Before(_ context.Context, _ string, user ffuser.User) (ffuser.User, error) {
+	return user, nil
+}
+
+// After does nothing.
+func (NoopHook) After(_ context.Context, _ string, _ ffuser.User, _ RawEvaluationDetails) {}
+
+// Error does nothing.
+func (NoopHook) Error(_ context.Context, _ string, _ ffuser.User, _ error) {}
+
+// Finally does nothing.
+func (NoopHook) Finally(_ context.Context, _ string, _ ffuser.User) {}
+
+// evaluateWithHooks calls the hooks of the configuration around the evaluation of the flag.
+func (g *GoFeatureFlag) evaluateWithHooks(ctx context.Context, flagKey string, user ffuser.User,
+	evaluate func(user ffuser.User) (interface{}, EvaluationDetails, error),
+	sdkDefault interface{}) (interface{}, EvaluationDetails, error) {
+	hooks := make([]Hook, 0, len(g.config.Hooks))
+	for _, hook := range g.config.Hooks {
+		if hook != nil {
+			hooks = append(hooks, hook)
+		}
+	}
+	if len(hooks) == 0 {
+		return evaluate(user)
+	}
+
+	// started are the hooks whose Before has been called, the last one first.
+	started := make([]Hook, 0, len(hooks))
+	defer func() {
+		for _, hook := range started {
+			hook.Finally(ctx, flagKey, user)
+		}
+	}()
+
+	for _, hook := range hooks {
+		started = append([]Hook{hook}, started...)
+		enrichedUser, err := hook.Before(ctx, flagKey, user)
+		if err != nil {
+			for _, h := range started {
+				h.Error(ctx, flagKey, user, err)
+			}
+			return sdkDefault, SDKDefaultDetails(ReasonError, ErrorCodeGeneral), err
+		}
+		user = enrichedUser
+	}
+
+	value, details, err := evaluate(user)
+	for _, hook := range started {
+		if err != nil {
+			hook.Error(ctx, flagKey, user, err)
+		} else {
+			hook.After(ctx, flagKey, user, RawEvaluationDetails{Value: value, EvaluationDetails: details})
+		}
+	}
+	return value, details, err
+}
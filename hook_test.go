@@ -0,0 +1,198 @@
+package ffclient_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+	"github.com/thomaspoignant/go-feature-flag/ffuser"
+)
+
+type ctxKey string
+
+// recordHook records the calls of the hooks and adds the beta attribute from the context to the user.
+type recordHook struct {
+	name      string
+	calls     *[]string
+	beforeErr error
+}
+
+func (h recordHook) Before(ctx context.Context, flagKey string, user ffuser.User) (ffuser.User, error) {
+	*h.calls = append(*h.calls, fmt.Sprintf("%s.Before(%s, %s)", h.name, flagKey, user.GetKey()))
+	if h.beforeErr != nil {
+		return user, h.beforeErr
+	}
+	if beta, ok := ctx.Value(ctxKey("beta")).(bool); ok {
+		return ffuser.NewUserBuilder(user.GetKey()).AddCustom("beta", beta).Build(), nil
+	}
+	return user, nil
+}
+
+func (h recordHook) After(_ context.Context, flagKey string, user ffuser.User, result ffclient.RawEvaluationDetails) {
+	*h.calls = append(*h.calls, fmt.Sprintf("%s.After(%s, %v, %v, %s)",
+		h.name, flagKey, user.GetCustom()["beta"], result.Value, result.Variation))
+}
+
+func (h recordHook) Error(_ context.Context, flagKey string, _ ffuser.User, err error) {
+	*h.calls = append(*h.calls, fmt.Sprintf("%s.Error(%s, %v)", h.name, flagKey, err))
+}
+
+func (h recordHook) Finally(_ context.Context, flagKey string, _ ffuser.User) {
+	*h.calls = append(*h.calls, fmt.Sprintf("%s.Finally(%s)", h.name, flagKey))
+}
+
+// afterOnlyHook uses NoopHook to implement only After.
+type afterOnlyHook struct {
+	ffclient.NoopHook
+	calls *[]string
+}
+
+func (h afterOnlyHook) After(_ context.Context, flagKey string, _ ffuser.User, _ ffclient.RawEvaluationDetails) {
+	*h.calls = append(*h.calls, "afterOnly.After("+flagKey+")")
+}
+
+func TestHooks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hooks")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	flagFile := filepath.Join(dir, "flags.yaml")
+	assert.NoError(t, ioutil.WriteFile(flagFile, []byte(`new-checkout:
+  variations:
+    A: "checkout-v1"
+    B: "checkout-v2"
+  targeting:
+    - name: beta-testers
+      query: beta eq true
+      variation: A
+  default: "checkout-v2"
+`), 0600))
+
+	var calls []string
+	goff, err := ffclient.New(ffclient.Config{
+		PollingInterval: 10 * time.Second,
+		Logger:          log.New(ioutil.Discard, "", 0),
+		Retriever:       &ffclient.FileRetriever{Path: flagFile},
+		Hooks: []ffclient.Hook{
+			recordHook{name: "first", calls: &calls},
+			nil,
+			afterOnlyHook{calls: &calls},
+		},
+	})
+	assert.NoError(t, err)
+	defer goff.Close()
+	user := ffuser.NewUser("user-1")
+
+	t.Run("Success with an enriched user", func(t *testing.T) {
+		calls = nil
+		ctx := context.WithValue(context.Background(), ctxKey("beta"), true)
+		got, err := goff.StringVariationCtx(ctx, "new-checkout", user, "default")
+		assert.NoError(t, err)
+		assert.Equal(t, "checkout-v1", got)
+		assert.Equal(t, []string{
+			"first.Before(new-checkout, user-1)",
+			"afterOnly.After(new-checkout)",
+			"first.After(new-checkout, true, checkout-v1, A)",
+			"first.Finally(new-checkout)",
+		}, calls)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		calls = nil
+		got, err := goff.BoolVariation("unknown-flag", user, true)
+		assert.Error(t, err)
+		assert.True(t, got)
+		assert.Equal(t, []string{
+			"first.Before(unknown-flag, user-1)",
+			"first.Error(unknown-flag, flag unknown-flag is not present or disabled)",
+			"first.Finally(unknown-flag)",
+		}, calls)
+	})
+}
+
+func TestHooks_beforeError(t *testing.T) {
+	var calls []string
+	goff, err := ffclient.New(ffclient.Config{
+		PollingInterval: 10 * time.Second,
+		Retriever:       &ffclient.FileRetriever{Path: "testdata/flag-config.yaml"},
+		Hooks: []ffclient.Hook{
+			recordHook{name: "first", calls: &calls},
+			recordHook{name: "second", calls: &calls, beforeErr: errors.New("no tenant in the context")},
+			recordHook{name: "third", calls: &calls},
+		},
+	})
+	assert.NoError(t, err)
+	defer goff.Close()
+
+	got, err := goff.BoolVariationDetails("test-flag", ffuser.NewUser("random-key"), false)
+	assert.EqualError(t, err, "no tenant in the context")
+	assert.False(t, got.Value)
+	assert.Equal(t, ffclient.ReasonError, got.Reason)
+	// the third hook has not been started, it is not called.
+	assert.Equal(t, []string{
+		"first.Before(test-flag, random-key)",
+		"second.Before(test-flag, random-key)",
+		"second.Error(test-flag, no tenant in the context)",
+		"first.Error(test-flag, no tenant in the context)",
+		"second.Finally(test-flag)",
+		"first.Finally(test-flag)",
+	}, calls)
+}
+
+func TestHooks_order(t *testing.T) {
+	var calls []string
+	goff, err := ffclient.New(ffclient.Config{
+		PollingInterval: 10 * time.Second,
+		Retriever:       &ffclient.FileRetriever{Path: "testdata/flag-config.yaml"},
+		Hooks: []ffclient.Hook{
+			recordHook{name: "first", calls: &calls},
+			recordHook{name: "second", calls: &calls},
+			recordHook{name: "third", calls: &calls},
+		},
+	})
+	assert.NoError(t, err)
+	defer goff.Close()
+	user := ffuser.NewUser("random-key")
+
+	t.Run("Success", func(t *testing.T) {
+		calls = nil
+		_, err := goff.BoolVariation("test-flag", user, false)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"first.Before(test-flag, random-key)",
+			"second.Before(test-flag, random-key)",
+			"third.Before(test-flag, random-key)",
+			"third.After(test-flag, <nil>, true, True)",
+			"second.After(test-flag, <nil>, true, True)",
+			"first.After(test-flag, <nil>, true, True)",
+			"third.Finally(test-flag)",
+			"second.Finally(test-flag)",
+			"first.Finally(test-flag)",
+		}, calls)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		calls = nil
+		_, err := goff.BoolVariation("unknown-flag", user, false)
+		assert.Error(t, err)
+		assert.Equal(t, []string{
+			"first.Before(unknown-flag, random-key)",
+			"second.Before(unknown-flag, random-key)",
+			"third.Before(unknown-flag, random-key)",
+			"third.Error(unknown-flag, flag unknown-flag is not present or disabled)",
+			"second.Error(unknown-flag, flag unknown-flag is not present or disabled)",
+			"first.Error(unknown-flag, flag unknown-flag is not present or disabled)",
+			"third.Finally(unknown-flag)",
+			"second.Finally(unknown-flag)",
+			"first.Finally(unknown-flag)",
+		}, calls)
+	})
+}
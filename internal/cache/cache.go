@@ -1,26 +1,26 @@
 package cache
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
-	"strings"
 	"sync"
 
-	"github.com/pelletier/go-toml"
-
 	"github.com/thomaspoignant/go-feature-flag/internal/model"
 )
 
 type Cache interface {
 	UpdateCache(loadedFlags []byte, fileFormat string) error
+	UpdateCacheFromFiles(files []FlagFile) ([]MergeConflict, error)
 	Close()
 	GetFlag(key string) (model.Flag, error)
+	AllFlags() (FlagsCache, error)
+	Snapshot() (Snapshot, error)
+	GetSegment(name string) (model.Segment, bool)
 }
 
 type cacheImpl struct {
 	flagsCache          FlagsCache
+	segmentsCache       SegmentsCache
 	mutex               sync.RWMutex
 	notificationService Service
 }
@@ -28,36 +28,70 @@ type cacheImpl struct {
 func New(notificationService Service) Cache {
 	return &cacheImpl{
 		flagsCache:          make(map[string]model.FlagData),
+		segmentsCache:       make(map[string]model.Segment),
 		mutex:               sync.RWMutex{},
 		notificationService: notificationService,
 	}
 }
 
 func (c *cacheImpl) UpdateCache(loadedFlags []byte, fileFormat string) error {
-	var newCache FlagsCache
-	var err error
-	switch strings.ToLower(fileFormat) {
-	case "toml":
-		err = toml.Unmarshal(loadedFlags, &newCache)
-	case "json":
-		err = json.Unmarshal(loadedFlags, &newCache)
-	default:
-		// default unmarshaller is YAML
-		err = yaml.Unmarshal(loadedFlags, &newCache)
+	newCache, newSegments, err := unmarshalFlagFile(loadedFlags, fileFormat)
+	if err != nil {
+		return err
+	}
+	return c.replaceCache(newCache, newSegments)
+}
+
+// ParseFlagFile reads a flag file and validates it with the same checks as UpdateCache.
+func ParseFlagFile(loadedFlags []byte, fileFormat string) (FlagsCache, SegmentsCache, error) {
+	flags, segments, err := unmarshalFlagFile(loadedFlags, fileFormat)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := validateFlagSet(flags, segments); err != nil {
+		return nil, nil, err
 	}
+	return flags, segments, nil
+}
 
+// UpdateCacheFromFiles merges the flag files and replace the cache with the result.
+// When a flag or a segment is defined in more than one file, the last file wins and
+// the conflict is returned. If one file is invalid, the cache is not updated.
+func (c *cacheImpl) UpdateCacheFromFiles(files []FlagFile) ([]MergeConflict, error) {
+	newCache, newSegments, conflicts, err := MergeFlagFiles(files)
 	if err != nil {
+		return nil, err
+	}
+	if err := c.replaceCache(newCache, newSegments); err != nil {
+		return nil, err
+	}
+	return conflicts, nil
+}
+
+// validateFlagSet runs all the checks on the flags and segments before using them.
+func validateFlagSet(flags FlagsCache, segments SegmentsCache) error {
+	if err := ValidateFlags(flags, segments); err != nil {
+		return err
+	}
+	return checkPrerequisitesCycle(flags)
+}
+
+// replaceCache replaces the content of the cache and notify the changes.
+func (c *cacheImpl) replaceCache(newCache FlagsCache, newSegments SegmentsCache) error {
+	if err := validateFlagSet(newCache, newSegments); err != nil {
 		return err
 	}
 
 	c.mutex.Lock()
 	// copy cache for difference checks async
 	cacheCopy := c.flagsCache.Copy()
+	segmentsCopy := c.segmentsCache.Copy()
 	c.flagsCache = newCache
+	c.segmentsCache = newSegments
 	c.mutex.Unlock()
 
 	// notify the changes
-	c.notificationService.Notify(cacheCopy, newCache)
+	c.notificationService.Notify(cacheCopy, newCache, segmentsCopy, newSegments)
 	return nil
 }
 
@@ -65,6 +99,7 @@ func (c *cacheImpl) Close() {
 	// Clear the cache
 	c.mutex.Lock()
 	c.flagsCache = nil
+	c.segmentsCache = nil
 	c.mutex.Unlock()
 	if c.notificationService != nil {
 		c.notificationService.Close()
@@ -86,3 +121,55 @@ func (c *cacheImpl) GetFlag(key string) (model.Flag, error) {
 
 	return &flag, nil
 }
+
+// AllFlags returns a copy of all the flags of the cache, the copy is done in one read-lock
+// so every flag comes from the same version of the configuration.
+func (c *cacheImpl) AllFlags() (FlagsCache, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if c.flagsCache == nil {
+		return nil, errors.New("impossible to read the flags before the initialisation")
+	}
+	return c.flagsCache.Copy(), nil
+}
+
+// Snapshot returns a copy of the flags and segments of the cache, the copy is done in one read-lock
+// so the prerequisites and segments used by the flags come from the same version of the configuration.
+func (c *cacheImpl) Snapshot() (Snapshot, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if c.flagsCache == nil {
+		return Snapshot{}, errors.New("impossible to read the flags before the initialisation")
+	}
+	return Snapshot{Flags: c.flagsCache.Copy(), Segments: c.segmentsCache.Copy()}, nil
+}
+
+// Snapshot is a copy of the flags and segments of the cache, use it to evaluate several flags
+// with the same version of the configuration.
+type Snapshot struct {
+	Flags    FlagsCache
+	Segments SegmentsCache
+}
+
+// GetFlag returns the flag with this key, an error is returned if the flag does not exist.
+func (s Snapshot) GetFlag(key string) (model.Flag, error) {
+	flag, ok := s.Flags[key]
+	if !ok {
+		return &model.FlagData{}, fmt.Errorf("flag [%v] does not exists", key)
+	}
+	return &flag, nil
+}
+
+// GetSegment returns the segment with this name, false if the segment does not exist.
+func (s Snapshot) GetSegment(name string) (model.Segment, bool) {
+	return s.Segments.GetSegment(name)
+}
+
+// GetSegment returns the segment with this name, false if the segment does not exist.
+func (c *cacheImpl) GetSegment(name string) (model.Segment, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.segmentsCache.GetSegment(name)
+}
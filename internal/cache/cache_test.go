@@ -17,6 +17,13 @@ func Test_FlagCacheNotInit(t *testing.T) {
 	assert.Error(t, err, "We should have an error if the cache is not init")
 }
 
+func Test_AllFlagsNotInit(t *testing.T) {
+	fCache := cache.New(nil)
+	fCache.Close()
+	_, err := fCache.AllFlags()
+	assert.Error(t, err, "We should have an error if the cache is not init")
+}
+
 func Test_GetFlagNotExist(t *testing.T) {
 	fCache := cache.New(nil)
 	_, err := fCache.GetFlag("not-exists-flag")
@@ -31,6 +38,7 @@ func Test_FlagCache(t *testing.T) {
   false: false
   default: false
   trackEvents: false
+  clientSide: true
 `)
 
 	jsonFile := []byte(`{
@@ -77,6 +85,7 @@ disable = false`)
 					False:       testconvert.Interface(false),
 					Default:     testconvert.Interface(false),
 					TrackEvents: testconvert.Bool(false),
+					ClientSide:  testconvert.Bool(true),
 				},
 			},
 			wantErr: false,
@@ -145,6 +154,206 @@ disable = false`)
 			},
 			wantErr: false,
 		},
+		{
+			name:       "Yaml with variations",
+			flagFormat: "yaml",
+			args: args{
+				loadedFlags: []byte(`test-flag:
+  variations:
+    control: "A"
+    blue: "B"
+  percentages:
+    control: 60
+    blue: 40
+  default: "default"
+`),
+			},
+			expected: map[string]model.FlagData{
+				"test-flag": {
+					Variations:  map[string]interface{}{"control": "A", "blue": "B"},
+					Percentages: map[string]float64{"control": 60, "blue": 40},
+					Default:     testconvert.Interface("default"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:       "Yaml with prerequisites",
+			flagFormat: "yaml",
+			args: args{
+				loadedFlags: []byte(`new-checkout-ui:
+  prerequisites:
+    - key: new-payment-backend
+      variation: "True"
+  true: true
+  false: false
+  default: false
+
+new-payment-backend:
+  true: true
+  false: false
+  default: false
+`),
+			},
+			expected: map[string]model.FlagData{
+				"new-checkout-ui": {
+					Prerequisites: []model.Prerequisite{{Key: "new-payment-backend", Variation: "True"}},
+					True:          testconvert.Interface(true),
+					False:         testconvert.Interface(false),
+					Default:       testconvert.Interface(false),
+				},
+				"new-payment-backend": {
+					True:    testconvert.Interface(true),
+					False:   testconvert.Interface(false),
+					Default: testconvert.Interface(false),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:       "Yaml with targets",
+			flagFormat: "yaml",
+			args: args{
+				loadedFlags: []byte(`test-flag:
+  targets:
+    qa-user: "True"
+    customer-42: "False"
+  percentage: 10
+  true: true
+  false: false
+  default: false
+`),
+			},
+			expected: map[string]model.FlagData{
+				"test-flag": {
+					Targets:    map[string]string{"qa-user": "True", "customer-42": "False"},
+					Percentage: testconvert.Float64(10),
+					True:       testconvert.Interface(true),
+					False:      testconvert.Interface(false),
+					Default:    testconvert.Interface(false),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:       "JSON with variations",
+			flagFormat: "json",
+			args: args{
+				loadedFlags: []byte(`{
+  "test-flag": {
+    "variations": {"control": "A", "blue": "B"},
+    "percentages": {"control": 60, "blue": 40},
+    "default": "default"
+  }
+}`),
+			},
+			expected: map[string]model.FlagData{
+				"test-flag": {
+					Variations:  map[string]interface{}{"control": "A", "blue": "B"},
+					Percentages: map[string]float64{"control": 60, "blue": 40},
+					Default:     testconvert.Interface("default"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:       "TOML with variations",
+			flagFormat: "toml",
+			args: args{
+				loadedFlags: []byte(`[test-flag]
+default = "default"
+
+  [test-flag.variations]
+  control = "A"
+  blue = "B"
+
+  [test-flag.percentages]
+  control = 60.0
+  blue = 40.0`),
+			},
+			expected: map[string]model.FlagData{
+				"test-flag": {
+					Variations:  map[string]interface{}{"control": "A", "blue": "B"},
+					Percentages: map[string]float64{"control": 60, "blue": 40},
+					Default:     testconvert.Interface("default"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:       "Yaml with targeting rules",
+			flagFormat: "yaml",
+			args: args{
+				loadedFlags: []byte(`test-flag:
+  targeting:
+    - name: beta-testers
+      query: beta eq true
+      variation: "True"
+    - query: country eq "FR"
+      percentage: 30
+  true: true
+  false: false
+  default: false
+`),
+			},
+			expected: map[string]model.FlagData{
+				"test-flag": {
+					Targeting: []model.TargetingRule{
+						{
+							Name:      testconvert.String("beta-testers"),
+							Query:     testconvert.String("beta eq true"),
+							Variation: testconvert.String("True"),
+						},
+						{
+							Query:      testconvert.String("country eq \"FR\""),
+							Percentage: testconvert.Float64(30),
+						},
+					},
+					True:    testconvert.Interface(true),
+					False:   testconvert.Interface(false),
+					Default: testconvert.Interface(false),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:       "TOML with targeting rules",
+			flagFormat: "toml",
+			args: args{
+				loadedFlags: []byte(`[test-flag]
+true = true
+false = false
+default = false
+
+  [[test-flag.targeting]]
+  name = "beta-testers"
+  query = "beta eq true"
+  variation = "True"
+
+  [[test-flag.targeting]]
+  query = "country eq \"FR\""
+  percentage = 30.0`),
+			},
+			expected: map[string]model.FlagData{
+				"test-flag": {
+					Targeting: []model.TargetingRule{
+						{
+							Name:      testconvert.String("beta-testers"),
+							Query:     testconvert.String("beta eq true"),
+							Variation: testconvert.String("True"),
+						},
+						{
+							Query:      testconvert.String("country eq \"FR\""),
+							Percentage: testconvert.Float64(30),
+						},
+					},
+					True:    testconvert.Interface(true),
+					False:   testconvert.Interface(false),
+					Default: testconvert.Interface(false),
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "TOML invalid file",
 			args: args{
@@ -181,8 +390,325 @@ disable = false`),
 				assert.Equal(t, expected.GetTrackEvents(), got.GetTrackEvents())
 				assert.Equal(t, expected.GetDisable(), got.GetDisable())
 				assert.Equal(t, expected.GetRollout(), got.GetRollout())
+				assert.Equal(t, expected.GetVariations(), got.GetVariations())
+				assert.Equal(t, expected.GetPercentages(), got.GetPercentages())
+				assert.Equal(t, expected.GetTargeting(), got.GetTargeting())
+				assert.Equal(t, expected.GetClientSide(), got.GetClientSide())
+				assert.Equal(t, expected.GetPrerequisites(), got.GetPrerequisites())
+				assert.Equal(t, expected.GetTargets(), got.GetTargets())
 			}
+
+			allFlags, err := fCache.AllFlags()
+			assert.NoError(t, err)
+			assert.Len(t, allFlags, len(tt.expected))
+			fCache.Close()
+		})
+	}
+}
+
+func Test_FlagCacheSegments(t *testing.T) {
+	tests := []struct {
+		name        string
+		loadedFlags []byte
+		flagFormat  string
+	}{
+		{
+			name:       "YAML",
+			flagFormat: "yaml",
+			loadedFlags: []byte(`segments:
+  beta-testers:
+    included: ["user-1", "user-2"]
+    excluded: ["user-3"]
+    rule: email ew "@example.com"
+
+test-flag:
+  rule: segment eq "beta-testers"
+  percentage: 100
+  true: true
+  false: false
+  default: false
+`),
+		},
+		{
+			name:       "JSON",
+			flagFormat: "json",
+			loadedFlags: []byte(`{
+  "segments": {
+    "beta-testers": {
+      "included": ["user-1", "user-2"],
+      "excluded": ["user-3"],
+      "rule": "email ew \"@example.com\""
+    }
+  },
+  "test-flag": {
+    "rule": "segment eq \"beta-testers\"",
+    "percentage": 100,
+    "true": true,
+    "false": false,
+    "default": false
+  }
+}`),
+		},
+		{
+			name:       "TOML",
+			flagFormat: "toml",
+			loadedFlags: []byte(`[segments.beta-testers]
+included = ["user-1", "user-2"]
+excluded = ["user-3"]
+rule = "email ew \"@example.com\""
+
+[test-flag]
+rule = "segment eq \"beta-testers\""
+percentage = 100.0
+true = true
+false = false
+default = false
+`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fCache := cache.New(cache.NewNotificationService([]notifier.Notifier{}))
+			err := fCache.UpdateCache(tt.loadedFlags, tt.flagFormat)
+			assert.NoError(t, err)
+
+			segment, ok := fCache.GetSegment("beta-testers")
+			assert.True(t, ok)
+			assert.Equal(t, model.Segment{
+				Included: []string{"user-1", "user-2"},
+				Excluded: []string{"user-3"},
+				Rule:     testconvert.String("email ew \"@example.com\""),
+			}, segment)
+
+			// segments is a reserved key, it is not a flag
+			_, err = fCache.GetFlag("segments")
+			assert.Error(t, err)
+
+			flag, err := fCache.GetFlag("test-flag")
+			assert.NoError(t, err)
+			assert.Equal(t, "segment eq \"beta-testers\"", flag.GetRule())
 			fCache.Close()
 		})
 	}
 }
+
+func Test_FlagCacheFlagNamedSegments(t *testing.T) {
+	tests := []struct {
+		name        string
+		loadedFlags []byte
+		flagFormat  string
+		wantErr     string
+	}{
+		{
+			name:       "YAML",
+			flagFormat: "yaml",
+			loadedFlags: []byte(`segments:
+  rule: key eq "random-key"
+  percentage: 100
+  true: true
+  false: false
+  default: false
+`),
+			wantErr: `invalid segments: it should be a map of segments, segments is a reserved key ` +
+				`for the segments and cannot be used as a flag name`,
+		},
+		{
+			name:       "JSON",
+			flagFormat: "json",
+			loadedFlags: []byte(`{
+  "segments": {
+    "variations": {"A": true, "B": false},
+    "defaultRule": {"variation": "A"}
+  }
+}`),
+			wantErr: `invalid segments: unknown field "variation" in the segment "defaultRule", segments is a ` +
+				`reserved key for the segments and cannot be used as a flag name`,
+		},
+		{
+			name:       "TOML",
+			flagFormat: "toml",
+			loadedFlags: []byte(`segments = "beta-testers"
+`),
+			wantErr: `invalid segments: it should be a table of segments, segments is a reserved key ` +
+				`for the segments and cannot be used as a flag name`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fCache := cache.New(cache.NewNotificationService([]notifier.Notifier{}))
+			defer fCache.Close()
+			err := fCache.UpdateCache(tt.loadedFlags, tt.flagFormat)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_FlagCachePrerequisitesCycle(t *testing.T) {
+	fCache := cache.New(cache.NewNotificationService([]notifier.Notifier{}))
+	err := fCache.UpdateCache([]byte(`flag-a:
+  prerequisites:
+    - key: flag-b
+      variation: "True"
+  true: true
+  false: false
+  default: false
+
+flag-b:
+  prerequisites:
+    - key: flag-a
+      variation: "True"
+  true: true
+  false: false
+  default: false
+`), "yaml")
+	assert.Error(t, err, "A cycle in the prerequisites should not be loaded")
+
+	_, err = fCache.GetFlag("flag-a")
+	assert.Error(t, err, "The cache should not be updated")
+}
+
+func Test_FlagCacheInvalidPrerequisites(t *testing.T) {
+	fCache := cache.New(cache.NewNotificationService([]notifier.Notifier{}))
+	defer fCache.Close()
+	err := fCache.UpdateCache([]byte(`flag-a:
+  prerequisites:
+    - key: unknown-flag
+      variation: "True"
+  true: true
+  false: false
+  default: false
+  rollout:
+    scheduled:
+      steps:
+        - date: 2021-01-01T00:00:00Z
+          prerequisites:
+            - key: flag-b
+              variation: "green"
+
+flag-b:
+  variations:
+    blue: "#0000FF"
+  default: "#FFFFFF"
+`), "yaml")
+	assert.Error(t, err, "A prerequisite on an unknown flag should not be loaded")
+
+	validationErr, ok := err.(*cache.ValidationError)
+	assert.True(t, ok, "the error should be a *cache.ValidationError")
+	assert.Equal(t, []model.ValidationError{
+		{Key: "flag-a", Field: "prerequisites[0]", Message: `unknown flag "unknown-flag"`},
+		{Key: "flag-a", Field: "rollout.scheduled.steps[0].prerequisites[0]",
+			Message: `flag "flag-b" has no variation "green"`},
+	}, validationErr.Errors)
+}
+
+func Test_FlagCacheUpdateFromFiles(t *testing.T) {
+	fCache := cache.New(cache.NewNotificationService([]notifier.Notifier{}))
+	defer fCache.Close()
+
+	conflicts, err := fCache.UpdateCacheFromFiles([]cache.FlagFile{
+		{
+			Source: "platform",
+			Content: []byte(`shared-flag:
+  true: "platform"
+  false: "false"
+  default: "default"
+
+platform-flag:
+  true: true
+  false: false
+  default: false
+
+segments:
+  beta:
+    included: ["a"]
+`),
+			FileFormat: "yaml",
+		},
+		{
+			Source: "team",
+			Content: []byte(`{
+  "shared-flag": {"true": "team", "false": "false", "default": "default"},
+  "team-flag": {"true": true, "false": false, "default": false}
+}`),
+			FileFormat: "json",
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []cache.MergeConflict{
+		{Key: "shared-flag", Sources: []string{"platform", "team"}},
+	}, conflicts)
+	assert.Equal(t, "flag [shared-flag] is defined in [platform team], using the one from team",
+		conflicts[0].String())
+
+	flag, err := fCache.GetFlag("shared-flag")
+	assert.NoError(t, err)
+	assert.Equal(t, "team", flag.GetTrue(), "the last file should win")
+
+	_, err = fCache.GetFlag("platform-flag")
+	assert.NoError(t, err)
+	_, err = fCache.GetFlag("team-flag")
+	assert.NoError(t, err)
+	_, ok := fCache.GetSegment("beta")
+	assert.True(t, ok)
+}
+
+func Test_FlagCacheUpdateFromFilesInvalidFile(t *testing.T) {
+	fCache := cache.New(cache.NewNotificationService([]notifier.Notifier{}))
+	defer fCache.Close()
+
+	err := fCache.UpdateCache([]byte(`test-flag:
+  true: true
+  false: false
+  default: false
+`), "yaml")
+	assert.NoError(t, err)
+
+	_, err = fCache.UpdateCacheFromFiles([]cache.FlagFile{
+		{Source: "valid", Content: []byte(`other-flag:
+  true: true
+  false: false
+  default: false
+`), FileFormat: "yaml"},
+		{Source: "invalid", Content: []byte(`{invalid`), FileFormat: "json"},
+	})
+	assert.Error(t, err)
+
+	_, err = fCache.GetFlag("test-flag")
+	assert.NoError(t, err, "The cache should not be updated if one file is invalid")
+	_, err = fCache.GetFlag("other-flag")
+	assert.Error(t, err, "The cache should not be updated if one file is invalid")
+}
+
+func Test_FlagCacheInvalidFlag(t *testing.T) {
+	fCache := cache.New(cache.NewNotificationService([]notifier.Notifier{}))
+	defer fCache.Close()
+	err := fCache.UpdateCache([]byte(`test-flag:
+  true: true
+  false: false
+  default: false
+`), "yaml")
+	assert.NoError(t, err)
+
+	err = fCache.UpdateCache([]byte(`test-flag:
+  rule: key eq
+  true: true
+  false: false
+  default: false
+
+test-flag2:
+  percentage: 150
+  true: "true"
+  false: false
+  default: false
+`), "yaml")
+	assert.Error(t, err, "An invalid flag should not be loaded")
+
+	validationErr, ok := err.(*cache.ValidationError)
+	assert.True(t, ok, "the error should be a *cache.ValidationError")
+	assert.Len(t, validationErr.Errors, 3, "all the problems should be reported")
+
+	_, err = fCache.GetFlag("test-flag2")
+	assert.Error(t, err, "The cache should not be updated")
+	_, err = fCache.GetFlag("test-flag")
+	assert.NoError(t, err, "The previous cache should be kept")
+}
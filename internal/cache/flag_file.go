@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+	"gopkg.in/yaml.v3"
+
+	"github.com/thomaspoignant/go-feature-flag/internal/model"
+)
+
+// unmarshalFlagFile reads the content of a flag file.
+// The segments are read from the reserved top-level key segments, all the other keys are flags.
+func unmarshalFlagFile(loadedFlags []byte, fileFormat string) (FlagsCache, SegmentsCache, error) {
+	switch strings.ToLower(fileFormat) {
+	case "toml":
+		return unmarshalTOMLFlagFile(loadedFlags)
+	case "json":
+		return unmarshalJSONFlagFile(loadedFlags)
+	default:
+		// default unmarshaller is YAML
+		return unmarshalYAMLFlagFile(loadedFlags)
+	}
+}
+
+func unmarshalJSONFlagFile(loadedFlags []byte) (FlagsCache, SegmentsCache, error) {
+	var content map[string]json.RawMessage
+	if err := json.Unmarshal(loadedFlags, &content); err != nil {
+		return nil, nil, err
+	}
+
+	flags := make(FlagsCache, len(content))
+	segments := make(SegmentsCache)
+	for key, value := range content {
+		if key == segmentsKey {
+			var raw interface{}
+			if err := json.Unmarshal(value, &raw); err != nil {
+				return nil, nil, err
+			}
+			if err := checkSegments(raw); err != nil {
+				return nil, nil, err
+			}
+			if err := json.Unmarshal(value, &segments); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		var flag model.FlagData
+		if err := json.Unmarshal(value, &flag); err != nil {
+			return nil, nil, err
+		}
+		flags[key] = flag
+	}
+	return flags, segments, nil
+}
+
+func unmarshalYAMLFlagFile(loadedFlags []byte) (FlagsCache, SegmentsCache, error) {
+	var content map[string]yaml.Node
+	if err := yaml.Unmarshal(loadedFlags, &content); err != nil {
+		return nil, nil, err
+	}
+
+	flags := make(FlagsCache, len(content))
+	segments := make(SegmentsCache)
+	for key, value := range content {
+		value := value
+		if key == segmentsKey {
+			var raw interface{}
+			if err := value.Decode(&raw); err != nil {
+				return nil, nil, err
+			}
+			if err := checkSegments(raw); err != nil {
+				return nil, nil, err
+			}
+			if err := value.Decode(&segments); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		var flag model.FlagData
+		if err := value.Decode(&flag); err != nil {
+			return nil, nil, err
+		}
+		flags[key] = flag
+	}
+	return flags, segments, nil
+}
+
+func unmarshalTOMLFlagFile(loadedFlags []byte) (FlagsCache, SegmentsCache, error) {
+	tree, err := toml.LoadBytes(loadedFlags)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	segments := make(SegmentsCache)
+	if tree.Has(segmentsKey) {
+		segmentsTree, ok := tree.Get(segmentsKey).(*toml.Tree)
+		if !ok {
+			return nil, nil, reservedSegmentsKeyError("it should be a table of segments")
+		}
+		if err := checkSegments(segmentsTree.ToMap()); err != nil {
+			return nil, nil, err
+		}
+		if err := segmentsTree.Unmarshal(&segments); err != nil {
+			return nil, nil, err
+		}
+		if err := tree.Delete(segmentsKey); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	flags := make(FlagsCache)
+	if err := tree.Unmarshal(&flags); err != nil {
+		return nil, nil, err
+	}
+	return flags, segments, nil
+}
+
+// segmentFields are the fields of a segment.
+var segmentFields = map[string]bool{"included": true, "excluded": true, "rule": true}
+
+// checkSegments returns an error if the value of the segments key is not a map of segments,
+// it happens when a flag is named segments.
+func checkSegments(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	segments, ok := value.(map[string]interface{})
+	if !ok {
+		return reservedSegmentsKeyError("it should be a map of segments")
+	}
+	for _, name := range sortedKeys(segments) {
+		if segments[name] == nil {
+			continue
+		}
+		fields, ok := segments[name].(map[string]interface{})
+		if !ok {
+			return reservedSegmentsKeyError(fmt.Sprintf("the segment %q should be a map", name))
+		}
+		for _, field := range sortedKeys(fields) {
+			if !segmentFields[field] {
+				return reservedSegmentsKeyError(fmt.Sprintf("unknown field %q in the segment %q", field, name))
+			}
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func reservedSegmentsKeyError(reason string) error {
+	return fmt.Errorf("invalid %s: %s, %s is a reserved key for the segments and cannot be used as a flag name",
+		segmentsKey, reason, segmentsKey)
+}
@@ -0,0 +1,226 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml"
+	"gopkg.in/yaml.v3"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// ConvertFlagFile converts the content of a flag file from one format to another (yaml, json or toml).
+// The file is not validated, it is converted as it is.
+func ConvertFlagFile(content []byte, fromFormat string, toFormat string) ([]byte, error) {
+	flags, segments, err := unmarshalFlagFile(content, fromFormat)
+	if err != nil {
+		return nil, err
+	}
+	return MarshalFlagFile(flags, segments, toFormat)
+}
+
+// MarshalFlagFile writes the flags and the segments in a flag file of the format (yaml, json or toml).
+// The fields not set in the flags are not written in the file, so the file reads back to the same flags.
+func MarshalFlagFile(flags FlagsCache, segments SegmentsCache, fileFormat string) ([]byte, error) {
+	content := make(map[string]interface{}, len(flags)+1)
+	for key, flag := range flags {
+		if key == segmentsKey {
+			return nil, fmt.Errorf("%s is a reserved key and cannot be used as a flag name", segmentsKey)
+		}
+		content[key] = flag
+	}
+	if len(segments) > 0 {
+		content[segmentsKey] = segments
+	}
+
+	switch strings.ToLower(fileFormat) {
+	case "toml":
+		return marshalTOMLFlagFile(content)
+	case "json":
+		return json.MarshalIndent(content, "", "  ")
+	case "yaml", "yml":
+		return yaml.Marshal(content)
+	default:
+		return nil, fmt.Errorf("unknown file format %q", fileFormat)
+	}
+}
+
+// marshalTOMLFlagFile writes the content in TOML.
+// We cannot use toml.Marshal directly because it writes the dates as strings that we are not able to read back,
+// so we first convert the content into maps keeping the native types.
+func marshalTOMLFlagFile(content map[string]interface{}) (result []byte, err error) {
+	value, _ := tomlValue(reflect.ValueOf(content))
+	if err := checkTOMLArrays(value, ""); err != nil {
+		return nil, err
+	}
+
+	// go-toml panics on the values it is not able to write, we don't want to crash the caller.
+	defer func() {
+		if info := recover(); info != nil {
+			result, err = nil, fmt.Errorf("impossible to write the flag file in TOML: %v", info)
+		}
+	}()
+	tree, err := toml.TreeFromMap(value.(map[string]interface{}))
+	if err != nil {
+		return nil, err
+	}
+	writeTOMLDates(tree)
+	return tree.Marshal()
+}
+
+// checkTOMLArrays returns an error if an array contains values of different types,
+// they are valid in YAML and JSON but not in TOML.
+func checkTOMLArrays(value interface{}, path string) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := checkTOMLArrays(v[key], strings.TrimPrefix(path+"."+key, ".")); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			if tomlType(item) != tomlType(v[0]) {
+				return fmt.Errorf("%s: TOML does not support arrays with different types (%s and %s)",
+					path, tomlType(v[0]), tomlType(item))
+			}
+			if err := checkTOMLArrays(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// tomlType returns the TOML type of a value converted by tomlValue.
+func tomlType(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "table"
+	case []interface{}:
+		return "array"
+	case time.Time:
+		return "datetime"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float32, float64:
+		return "float"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "integer"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// writeTOMLDates replaces the dates of the tree by their raw representation with the fractions of seconds,
+// go-toml is writing the dates without them.
+func writeTOMLDates(tree *toml.Tree) {
+	for _, key := range tree.Keys() {
+		switch v := tree.GetPath([]string{key}).(type) {
+		case time.Time:
+			tree.SetPath([]string{key}, []byte(v.Format(time.RFC3339Nano)))
+		case *toml.Tree:
+			writeTOMLDates(v)
+		case []*toml.Tree:
+			for _, item := range v {
+				writeTOMLDates(item)
+			}
+		}
+	}
+}
+
+// tomlValue converts a value in maps, slices and basic types using the toml tags of the structs.
+// It returns false if the value is nil and should not be written in the file.
+func tomlValue(v reflect.Value) (interface{}, bool) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, false
+		}
+		return tomlValue(v.Elem())
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return v.Interface(), true
+		}
+		result := make(map[string]interface{})
+		tomlStructFields(v, result)
+		return result, true
+	case reflect.Map:
+		result := make(map[string]interface{}, v.Len())
+		for _, key := range v.MapKeys() {
+			if value, ok := tomlValue(v.MapIndex(key)); ok {
+				result[fmt.Sprint(key.Interface())] = value
+			}
+		}
+		return result, true
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil, false
+		}
+		result := make([]interface{}, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if value, ok := tomlValue(v.Index(i)); ok {
+				result = append(result, value)
+			}
+		}
+		return result, true
+	default:
+		return v.Interface(), true
+	}
+}
+
+// tomlStructFields adds the fields of the struct in the map, the embedded structs are flattened.
+func tomlStructFields(v reflect.Value, result map[string]interface{}) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		tag := field.Tag.Get("toml")
+		if field.PkgPath != "" || tag == "-" {
+			continue
+		}
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			tomlStructFields(v.Field(i), result)
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+		// like in JSON, omitempty omits the pointers only when they are nil.
+		omitEmpty := strings.Contains(tag, "omitempty") && field.Type.Kind() != reflect.Ptr
+		if omitEmpty && v.Field(i).IsZero() {
+			continue
+		}
+		value, ok := tomlValue(v.Field(i))
+		if !ok || (omitEmpty && isEmptyTOMLValue(value)) {
+			continue
+		}
+		result[name] = value
+	}
+}
+
+// isEmptyTOMLValue returns true for the empty tables and arrays, they are omitted when the field has omitempty.
+// A pointer to a zero value (ex: percentage = 0) is never omitted because it is not the same thing
+// as a field not set.
+func isEmptyTOMLValue(value interface{}) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,150 @@
+package cache_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thomaspoignant/go-feature-flag/internal/cache"
+	"github.com/thomaspoignant/go-feature-flag/internal/model"
+	"github.com/thomaspoignant/go-feature-flag/testutils/testconvert"
+)
+
+func TestConvertFlagFile(t *testing.T) {
+	// the fractions of seconds should be kept in every format.
+	start := time.Date(2021, 2, 1, 10, 10, 10, 123456789, time.UTC)
+	flags := cache.FlagsCache{
+		"rollout-flag": model.FlagData{
+			Rule:        testconvert.String(`key eq "random-key"`),
+			Percentage:  testconvert.Float64(0),
+			True:        testconvert.Interface("on"),
+			False:       testconvert.Interface("off"),
+			Default:     testconvert.Interface("off"),
+			TrackEvents: testconvert.Bool(false),
+			Rollout: &model.Rollout{
+				Progressive: &model.Progressive{
+					Percentage:  model.ProgressivePercentage{Initial: 10, End: 90},
+					ReleaseRamp: model.ProgressiveReleaseRamp{Start: testconvert.Time(start)},
+				},
+				Scheduled: &model.ScheduledRollout{
+					Steps: []model.ScheduledStep{
+						{FlagData: model.FlagData{Percentage: testconvert.Float64(0)}, Date: testconvert.Time(start)},
+						{FlagData: model.FlagData{Rule: testconvert.String("")}, Date: testconvert.Time(start.Add(time.Hour))},
+					},
+				},
+			},
+		},
+		"variation-flag": model.FlagData{
+			Variations: map[string]interface{}{
+				"A": map[string]interface{}{"color": "blue"},
+				"B": map[string]interface{}{"color": "red"},
+			},
+			Percentages: map[string]float64{"A": 50, "B": 50},
+			Default:     testconvert.Interface(map[string]interface{}{}),
+			Targeting: []model.TargetingRule{
+				{Name: testconvert.String("beta"), Query: testconvert.String(`beta eq true`), Variation: testconvert.String("B")},
+			},
+			Prerequisites: []model.Prerequisite{{Key: "rollout-flag", Variation: "True"}},
+			Targets:       map[string]string{"user-1": "A"},
+			Disable:       testconvert.Bool(false),
+		},
+	}
+	segments := cache.SegmentsCache{
+		"beta": model.Segment{Included: []string{"user-1"}, Rule: testconvert.String(`beta eq true`)},
+	}
+
+	formats := []string{"yaml", "json", "toml"}
+	for _, from := range formats {
+		for _, to := range formats {
+			t.Run(from+" to "+to, func(t *testing.T) {
+				content, err := cache.MarshalFlagFile(flags, segments, from)
+				assert.NoError(t, err)
+
+				converted, err := cache.ConvertFlagFile(content, from, to)
+				assert.NoError(t, err)
+
+				gotFlags, gotSegments, err := cache.ParseFlagFile(converted, to)
+				assert.NoError(t, err)
+				assertSameJSON(t, flags, gotFlags)
+				assertSameJSON(t, segments, gotSegments)
+			})
+		}
+	}
+}
+
+func TestConvertFlagFile_testdata(t *testing.T) {
+	tests := []struct {
+		file   string
+		format string
+	}{
+		{file: "../../testdata/flag-config.yaml", format: "yaml"},
+		{file: "../../testdata/flag-config.json", format: "json"},
+		{file: "../../testdata/flag-config.toml", format: "toml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			content, err := ioutil.ReadFile(tt.file)
+			assert.NoError(t, err)
+			want, wantSegments, err := cache.ParseFlagFile(content, tt.format)
+			assert.NoError(t, err)
+
+			for _, to := range []string{"yaml", "json", "toml"} {
+				converted, err := cache.ConvertFlagFile(content, tt.format, to)
+				assert.NoError(t, err)
+				got, gotSegments, err := cache.ParseFlagFile(converted, to)
+				assert.NoError(t, err)
+				assertSameJSON(t, want, got)
+				assertSameJSON(t, wantSegments, gotSegments)
+			}
+		})
+	}
+}
+
+func TestConvertFlagFile_mixedArray(t *testing.T) {
+	content := []byte(`mixed-flag:
+  variations:
+    a: [1, "two"]
+    b: [{"color": "blue"}, "red"]
+  default: []
+  rollout:
+    experimentation:
+      start: 2021-02-01T10:10:10.5+02:00
+`)
+	want, _, err := cache.ParseFlagFile(content, "yaml")
+	assert.NoError(t, err)
+
+	for _, to := range []string{"yaml", "json"} {
+		converted, err := cache.ConvertFlagFile(content, "yaml", to)
+		assert.NoError(t, err)
+		got, _, err := cache.ParseFlagFile(converted, to)
+		assert.NoError(t, err)
+		assertSameJSON(t, want, got)
+		assert.True(t, want["mixed-flag"].Rollout.Experimentation.Start.Equal(
+			*got["mixed-flag"].Rollout.Experimentation.Start), "the date should be the same")
+	}
+
+	_, err = cache.ConvertFlagFile(content, "yaml", "toml")
+	assert.EqualError(t, err,
+		"mixed-flag.variations.a: TOML does not support arrays with different types (integer and string)")
+}
+
+func TestMarshalFlagFile_errors(t *testing.T) {
+	_, err := cache.MarshalFlagFile(cache.FlagsCache{"flag": {}}, nil, "xml")
+	assert.EqualError(t, err, `unknown file format "xml"`)
+
+	_, err = cache.MarshalFlagFile(cache.FlagsCache{"segments": {}}, nil, "yaml")
+	assert.EqualError(t, err, "segments is a reserved key and cannot be used as a flag name")
+}
+
+// assertSameJSON compares the JSON of the values, the numbers read from a JSON file are float64 when
+// they are int in YAML and TOML.
+func assertSameJSON(t *testing.T, want interface{}, got interface{}) {
+	wantJSON, err := json.Marshal(want)
+	assert.NoError(t, err)
+	gotJSON, err := json.Marshal(got)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(wantJSON), string(gotJSON))
+}
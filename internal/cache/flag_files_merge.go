@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"fmt"
+	"sort"
+)
+
+// FlagFile is the content of one flag file with the format used to read it.
+type FlagFile struct {
+	// Source is the name of the source of the file, it is used to report the conflicts.
+	Source string
+
+	Content    []byte
+	FileFormat string
+}
+
+// MergeConflict describes a key defined in more than one flag file.
+type MergeConflict struct {
+	// Key is the name of the flag or of the segment.
+	Key string
+
+	// Segment is true if the key is a segment.
+	Segment bool
+
+	// Sources are the sources defining the key, in the order of the files.
+	// The last one is the one kept.
+	Sources []string
+}
+
+func (m MergeConflict) String() string {
+	kind := "flag"
+	if m.Segment {
+		kind = "segment"
+	}
+	return fmt.Sprintf("%s [%s] is defined in %v, using the one from %s",
+		kind, m.Key, m.Sources, m.Sources[len(m.Sources)-1])
+}
+
+// MergeFlagFiles reads all the files and merge them in one flags cache and one segments cache.
+// When a key is defined in more than one file, the last file wins and a MergeConflict is returned.
+func MergeFlagFiles(files []FlagFile) (FlagsCache, SegmentsCache, []MergeConflict, error) {
+	flags := make(FlagsCache)
+	segments := make(SegmentsCache)
+	flagSources := make(map[string][]string)
+	segmentSources := make(map[string][]string)
+
+	for _, file := range files {
+		fileFlags, fileSegments, err := unmarshalFlagFile(file.Content, file.FileFormat)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("impossible to read the flags from %s: %v", file.Source, err)
+		}
+		for key, flag := range fileFlags {
+			flagSources[key] = append(flagSources[key], file.Source)
+			flags[key] = flag
+		}
+		for name, segment := range fileSegments {
+			segmentSources[name] = append(segmentSources[name], file.Source)
+			segments[name] = segment
+		}
+	}
+
+	conflicts := append(getConflicts(flagSources, false), getConflicts(segmentSources, true)...)
+	return flags, segments, conflicts, nil
+}
+
+// getConflicts returns the keys defined by more than one source, sorted by key.
+func getConflicts(sources map[string][]string, segment bool) []MergeConflict {
+	var conflicts []MergeConflict
+	for key, keySources := range sources {
+		if len(keySources) > 1 {
+			conflicts = append(conflicts, MergeConflict{Key: key, Segment: segment, Sources: keySources})
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Key < conflicts[j].Key
+	})
+	return conflicts
+}
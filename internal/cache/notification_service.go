@@ -2,43 +2,93 @@ package cache
 
 import (
 	"github.com/google/go-cmp/cmp"
+	"reflect"
 	"sync"
 
+	"github.com/thomaspoignant/go-feature-flag/internal/metrics"
 	"github.com/thomaspoignant/go-feature-flag/internal/model"
 	"github.com/thomaspoignant/go-feature-flag/internal/notifier"
 )
 
 type Service interface {
 	Close()
-	Notify(oldCache FlagsCache, newCache FlagsCache)
+	Notify(oldCache FlagsCache, newCache FlagsCache, oldSegments SegmentsCache, newSegments SegmentsCache)
 }
 
 func NewNotificationService(notifiers []notifier.Notifier) Service {
+	return NewNotificationServiceWithMetrics(notifiers, nil)
+}
+
+// NewNotificationServiceWithMetrics creates a notification service recording the failures of the notifiers.
+func NewNotificationServiceWithMetrics(notifiers []notifier.Notifier, recorder metrics.Recorder) Service {
 	return &notificationService{
-		Notifiers: notifiers,
-		waitGroup: &sync.WaitGroup{},
+		Notifiers:    notifiers,
+		waitGroup:    &sync.WaitGroup{},
+		metrics:      metrics.OrNoop(recorder),
+		lastNotified: make([]chan struct{}, len(notifiers)),
 	}
 }
 
 type notificationService struct {
 	Notifiers []notifier.Notifier
 	waitGroup *sync.WaitGroup
+	metrics   metrics.Recorder
+
+	// lastNotified are closed when the last notification of each notifier is done,
+	// a notifier receives the changes in the order of the updates of the cache.
+	lastNotified []chan struct{}
+	mutex        sync.Mutex
 }
 
-func (c *notificationService) Notify(oldCache FlagsCache, newCache FlagsCache) {
-	diff := c.getDifferences(oldCache, newCache)
+func (c *notificationService) Notify(
+	oldCache FlagsCache, newCache FlagsCache, oldSegments SegmentsCache, newSegments SegmentsCache) {
+	diff := Diff(oldCache, newCache, oldSegments, newSegments)
 	if diff.HasDiff() {
-		for _, notifier := range c.Notifiers {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		for i, n := range c.Notifiers {
+			previous, done := c.lastNotified[i], make(chan struct{})
+			c.lastNotified[i] = done
 			c.waitGroup.Add(1)
-			go notifier.Notify(diff, c.waitGroup)
+			go func(n notifier.Notifier, previous chan struct{}, done chan struct{}) {
+				// the notifier releases its own wait group, Close waits until the failure is recorded.
+				defer c.waitGroup.Done()
+				defer close(done)
+				if previous != nil {
+					// the previous changes have to be sent first.
+					<-previous
+				}
+				notifierWg := &sync.WaitGroup{}
+				notifierWg.Add(1)
+				if err := n.Notify(diff, notifierWg); err != nil {
+					c.metrics.RecordNotifierFailure(notifierName(n))
+				}
+			}(n, previous, done)
 		}
 	}
 }
 
+// Diff returns the differences between two versions of the flags and segments.
+func Diff(oldCache FlagsCache, newCache FlagsCache, oldSegments SegmentsCache, newSegments SegmentsCache) model.DiffCache {
+	c := &notificationService{}
+	diff := c.getDifferences(oldCache, newCache)
+	diff.Segments = c.getSegmentDifferences(oldSegments, newSegments)
+	return diff
+}
+
 func (c *notificationService) Close() {
 	c.waitGroup.Wait()
 }
 
+// notifierName returns the name of the type of the notifier (ex: WebhookNotifier).
+func notifierName(n notifier.Notifier) string {
+	t := reflect.TypeOf(n)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
 // getDifferences is checking what are the difference in the updated cache.
 func (c *notificationService) getDifferences(
 	oldCache FlagsCache, newCache FlagsCache) model.DiffCache {
@@ -71,3 +121,39 @@ func (c *notificationService) getDifferences(
 	}
 	return diff
 }
+
+// getSegmentDifferences is checking what are the difference in the updated segments,
+// it returns nil if no segment has changed.
+func (c *notificationService) getSegmentDifferences(
+	oldSegments SegmentsCache, newSegments SegmentsCache) *model.DiffSegments {
+	diff := model.DiffSegments{
+		Deleted: map[string]model.Segment{},
+		Added:   map[string]model.Segment{},
+		Updated: map[string]model.DiffSegmentUpdated{},
+	}
+	for key, oldSegment := range oldSegments {
+		newSegment, inNewSegments := newSegments[key]
+		if !inNewSegments {
+			diff.Deleted[key] = oldSegment
+			continue
+		}
+
+		if !cmp.Equal(oldSegment, newSegment) {
+			diff.Updated[key] = model.DiffSegmentUpdated{
+				Before: oldSegment,
+				After:  newSegment,
+			}
+		}
+	}
+
+	for key, newSegment := range newSegments {
+		if _, inOldSegments := oldSegments[key]; !inOldSegments {
+			diff.Added[key] = newSegment
+		}
+	}
+
+	if !diff.HasDiff() {
+		return nil
+	}
+	return &diff
+}
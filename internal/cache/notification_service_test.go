@@ -5,6 +5,7 @@ import (
 	"github.com/thomaspoignant/go-feature-flag/testutils/testconvert"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/thomaspoignant/go-feature-flag/internal/model"
 	"github.com/thomaspoignant/go-feature-flag/internal/notifier"
@@ -155,3 +156,94 @@ func Test_notificationService_getDifferences(t *testing.T) {
 		})
 	}
 }
+
+func Test_notificationService_getSegmentDifferences(t *testing.T) {
+	type args struct {
+		oldSegments SegmentsCache
+		newSegments SegmentsCache
+	}
+	tests := []struct {
+		name string
+		args args
+		want *model.DiffSegments
+	}{
+		{
+			name: "No change",
+			args: args{
+				oldSegments: SegmentsCache{"beta": {Included: []string{"user-1"}}},
+				newSegments: SegmentsCache{"beta": {Included: []string{"user-1"}}},
+			},
+			want: nil,
+		},
+		{
+			name: "Added, updated and deleted segments",
+			args: args{
+				oldSegments: SegmentsCache{
+					"beta":     {Included: []string{"user-1"}},
+					"internal": {Rule: testconvert.String("email ew \"@example.com\"")},
+				},
+				newSegments: SegmentsCache{
+					"beta":  {Included: []string{"user-1", "user-2"}},
+					"alpha": {Included: []string{"user-3"}},
+				},
+			},
+			want: &model.DiffSegments{
+				Deleted: map[string]model.Segment{
+					"internal": {Rule: testconvert.String("email ew \"@example.com\"")},
+				},
+				Added: map[string]model.Segment{
+					"alpha": {Included: []string{"user-3"}},
+				},
+				Updated: map[string]model.DiffSegmentUpdated{
+					"beta": {
+						Before: model.Segment{Included: []string{"user-1"}},
+						After:  model.Segment{Included: []string{"user-1", "user-2"}},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &notificationService{
+				waitGroup: &sync.WaitGroup{},
+			}
+			got := c.getSegmentDifferences(tt.args.oldSegments, tt.args.newSegments)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// orderNotifier records the flags added in each diff, the first notification is slow.
+type orderNotifier struct {
+	mutex sync.Mutex
+	added []string
+}
+
+func (n *orderNotifier) Notify(diff model.DiffCache, wg *sync.WaitGroup) error {
+	defer wg.Done()
+	if _, ok := diff.Added["flag-1"]; ok {
+		time.Sleep(100 * time.Millisecond)
+	}
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	for key := range diff.Added {
+		n.added = append(n.added, key)
+	}
+	return nil
+}
+
+func Test_notificationService_NotifyInOrder(t *testing.T) {
+	n := &orderNotifier{}
+	service := NewNotificationService([]notifier.Notifier{n})
+
+	flag := FlagsCache{"flag-1": model.FlagData{Percentage: testconvert.Float64(100)}}
+	service.Notify(FlagsCache{}, flag, nil, nil)
+	service.Notify(flag, FlagsCache{
+		"flag-1": model.FlagData{Percentage: testconvert.Float64(100)},
+		"flag-2": model.FlagData{Percentage: testconvert.Float64(100)},
+	}, nil, nil)
+	service.Close()
+
+	assert.Equal(t, []string{"flag-1", "flag-2"}, n.added)
+}
@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// persistentFile is the content of the file where we save the last flag files loaded.
+type persistentFile struct {
+	// UpdatedAt is the date when the flag files were retrieved.
+	UpdatedAt time.Time            `json:"updatedAt"`
+	Files     []persistentFlagFile `json:"files"`
+}
+
+type persistentFlagFile struct {
+	Source     string `json:"source"`
+	FileFormat string `json:"fileFormat,omitempty"`
+	Content    string `json:"content"`
+}
+
+// SavePersistentFile writes the flag files in path, the file is written atomically so
+// a crash during the write never leaves a corrupted file.
+func SavePersistentFile(path string, files []FlagFile, updatedAt time.Time) error {
+	content := persistentFile{
+		UpdatedAt: updatedAt,
+		Files:     make([]persistentFlagFile, 0, len(files)),
+	}
+	for _, file := range files {
+		content.Files = append(content.Files, persistentFlagFile{
+			Source:     file.Source,
+			FileFormat: file.FileFormat,
+			Content:    string(file.Content),
+		})
+	}
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+
+	tmpFile, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), path)
+}
+
+// LoadPersistentFile reads the flag files saved with SavePersistentFile and the date when they were retrieved.
+func LoadPersistentFile(path string) ([]FlagFile, time.Time, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	var content persistentFile
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	files := make([]FlagFile, 0, len(content.Files))
+	for _, file := range content.Files {
+		files = append(files, FlagFile{
+			Source:     file.Source,
+			FileFormat: file.FileFormat,
+			Content:    []byte(file.Content),
+		})
+	}
+	return files, content.UpdatedAt, nil
+}
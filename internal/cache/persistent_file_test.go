@@ -0,0 +1,49 @@
+package cache_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thomaspoignant/go-feature-flag/internal/cache"
+)
+
+func TestPersistentFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "persistent")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "flags.json")
+
+	files := []cache.FlagFile{
+		{Source: "yaml file", FileFormat: "yaml", Content: []byte("test-flag:\n  true: true\n")},
+		{Source: "json file", FileFormat: "json", Content: []byte(`{"test-flag2": {"true": true}}`)},
+	}
+	updatedAt := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+	assert.NoError(t, cache.SavePersistentFile(path, files, updatedAt))
+
+	got, gotUpdatedAt, err := cache.LoadPersistentFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, files, got)
+	assert.True(t, updatedAt.Equal(gotUpdatedAt))
+
+	tmpFiles, _ := filepath.Glob(filepath.Join(dir, "*.tmp*"))
+	assert.Empty(t, tmpFiles, "the temporary file should be removed")
+}
+
+func TestPersistentFile_errors(t *testing.T) {
+	err := cache.SavePersistentFile("/not-a-directory/flags.json", nil, time.Now())
+	assert.Error(t, err)
+
+	_, _, err = cache.LoadPersistentFile("/not-a-directory/flags.json")
+	assert.Error(t, err)
+
+	invalidFile, _ := ioutil.TempFile("", "")
+	defer os.Remove(invalidFile.Name())
+	_ = ioutil.WriteFile(invalidFile.Name(), []byte("{invalid"), 0600)
+	_, _, err = cache.LoadPersistentFile(invalidFile.Name())
+	assert.Error(t, err)
+}
@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+)
+
+// checkPrerequisitesCycle returns an error if a flag depends on itself through its prerequisites.
+func checkPrerequisitesCycle(flags FlagsCache) error {
+	// the zero value of the state is a flag not visited yet.
+	const (
+		inProgress = iota + 1
+		done
+	)
+	state := make(map[string]int, len(flags))
+
+	var visit func(key string, path []string) error
+	visit = func(key string, path []string) error {
+		switch state[key] {
+		case done:
+			return nil
+		case inProgress:
+			return fmt.Errorf("prerequisites cycle detected: %s", strings.Join(append(path, key), " -> "))
+		}
+
+		flag, ok := flags[key]
+		if !ok {
+			// unknown prerequisites are not met during the evaluation, they cannot create a cycle.
+			return nil
+		}
+
+		state[key] = inProgress
+		// the scheduled steps can change the prerequisites, we check every version of the flag.
+		for _, version := range flag.ScheduledVersions() {
+			for _, prerequisite := range version.GetPrerequisites() {
+				if err := visit(prerequisite.Key, append(path, key)); err != nil {
+					return err
+				}
+			}
+		}
+		state[key] = done
+		return nil
+	}
+
+	// keys are sorted to always report the same cycle.
+	for _, key := range sortedFlagKeys(flags) {
+		if err := visit(key, []string{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thomaspoignant/go-feature-flag/internal/model"
+	"github.com/thomaspoignant/go-feature-flag/testutils/testconvert"
+)
+
+func Test_checkPrerequisitesCycle(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   FlagsCache
+		wantErr string
+	}{
+		{
+			name: "No prerequisites",
+			flags: FlagsCache{
+				"flag-a": {},
+				"flag-b": {},
+			},
+		},
+		{
+			name: "Chain of prerequisites",
+			flags: FlagsCache{
+				"flag-a": {Prerequisites: []model.Prerequisite{{Key: "flag-b", Variation: "True"}}},
+				"flag-b": {Prerequisites: []model.Prerequisite{{Key: "flag-c", Variation: "True"}}},
+				"flag-c": {},
+				"flag-d": {Prerequisites: []model.Prerequisite{{Key: "flag-b", Variation: "True"}}},
+			},
+		},
+		{
+			name: "Unknown prerequisite",
+			flags: FlagsCache{
+				"flag-a": {Prerequisites: []model.Prerequisite{{Key: "unknown", Variation: "True"}}},
+			},
+		},
+		{
+			name: "Flag depending on itself",
+			flags: FlagsCache{
+				"flag-a": {Prerequisites: []model.Prerequisite{{Key: "flag-a", Variation: "True"}}},
+			},
+			wantErr: "prerequisites cycle detected: flag-a -> flag-a",
+		},
+		{
+			name: "Cycle between flags",
+			flags: FlagsCache{
+				"flag-a": {Prerequisites: []model.Prerequisite{{Key: "flag-b", Variation: "True"}}},
+				"flag-b": {Prerequisites: []model.Prerequisite{{Key: "flag-c", Variation: "True"}}},
+				"flag-c": {Prerequisites: []model.Prerequisite{{Key: "flag-a", Variation: "True"}}},
+			},
+			wantErr: "prerequisites cycle detected: flag-a -> flag-b -> flag-c -> flag-a",
+		},
+		{
+			name: "Cycle added by a scheduled step",
+			flags: FlagsCache{
+				"flag-a": {Prerequisites: []model.Prerequisite{{Key: "flag-b", Variation: "True"}}},
+				"flag-b": {
+					Rollout: &model.Rollout{Scheduled: &model.ScheduledRollout{Steps: []model.ScheduledStep{
+						{
+							FlagData: model.FlagData{
+								Prerequisites: []model.Prerequisite{{Key: "flag-a", Variation: "True"}},
+							},
+							Date: testconvert.Time(time.Now().Add(-time.Hour)),
+						},
+					}}},
+				},
+			},
+			wantErr: "prerequisites cycle detected: flag-a -> flag-b -> flag-a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkPrerequisitesCycle(tt.flags)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
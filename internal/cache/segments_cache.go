@@ -0,0 +1,22 @@
+package cache
+
+import model "github.com/thomaspoignant/go-feature-flag/internal/model"
+
+// segmentsKey is the reserved top-level key of the flag file containing the segments.
+const segmentsKey = "segments"
+
+type SegmentsCache map[string]model.Segment
+
+func (sc SegmentsCache) Copy() SegmentsCache {
+	copyCache := make(SegmentsCache)
+	for k, v := range sc {
+		copyCache[k] = v
+	}
+	return copyCache
+}
+
+// GetSegment returns the segment with this name, false if the segment does not exist.
+func (sc SegmentsCache) GetSegment(name string) (model.Segment, bool) {
+	segment, ok := sc[name]
+	return segment, ok
+}
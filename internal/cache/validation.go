@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/thomaspoignant/go-feature-flag/internal/model"
+)
+
+// ValidationError is returned when the flag file is invalid, it contains every problem found in the file.
+type ValidationError struct {
+	Errors []model.ValidationError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("invalid flag file, %d error(s): %s", len(e.Errors), strings.Join(messages, ", "))
+}
+
+// ValidateFlags checks all the flags and segments, it returns a *ValidationError if something is invalid.
+func ValidateFlags(flags FlagsCache, segments SegmentsCache) error {
+	var errs []model.ValidationError
+	for _, key := range sortedFlagKeys(flags) {
+		flag := flags[key]
+		errs = append(errs, flag.Validate(key)...)
+		errs = append(errs, validatePrerequisites(key, flag, flags)...)
+	}
+
+	names := make([]string, 0, len(segments))
+	for name := range segments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		segment := segments[name]
+		for _, err := range segment.Validate(name) {
+			err.Key = segmentsKey + "." + err.Key
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+func sortedFlagKeys(flags FlagsCache) []string {
+	keys := make([]string, 0, len(flags))
+	for key := range flags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// validatePrerequisites checks that the prerequisites of every version of the flag are existing flags
+// able to serve the expected variation.
+func validatePrerequisites(key string, flag model.FlagData, flags FlagsCache) []model.ValidationError {
+	var errs []model.ValidationError
+	reported := make(map[string]bool)
+	for i, version := range flag.ScheduledVersions() {
+		prefix := ""
+		if i > 0 {
+			prefix = fmt.Sprintf("rollout.scheduled.steps[%d].", i-1)
+		}
+		for j, prerequisite := range version.GetPrerequisites() {
+			field := fmt.Sprintf("prerequisites[%d]", j)
+			var message string
+			prerequisiteFlag, ok := flags[prerequisite.Key]
+			switch {
+			case prerequisite.Key == "":
+				// already reported by the validation of the flag.
+				continue
+			case !ok:
+				message = fmt.Sprintf("unknown flag %q", prerequisite.Key)
+			case !prerequisiteFlag.HasVariation(prerequisite.Variation):
+				message = fmt.Sprintf("flag %q has no variation %q", prerequisite.Key, prerequisite.Variation)
+			default:
+				continue
+			}
+			if reported[field+message] {
+				continue
+			}
+			reported[field+message] = true
+			errs = append(errs, model.ValidationError{Key: key, Field: prefix + field, Message: message})
+		}
+	}
+	return errs
+}
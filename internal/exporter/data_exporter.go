@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/thomaspoignant/go-feature-flag/internal/fflog"
+	"github.com/thomaspoignant/go-feature-flag/internal/metrics"
 )
 
 const defaultFlushInterval = 60 * time.Second
@@ -15,6 +16,13 @@ const defaultMaxEventInMemory = int64(100000)
 // NewDataExporterScheduler allows to create a new instance of DataExporterScheduler ready to be used to export data.
 func NewDataExporterScheduler(ctx context.Context, flushInterval time.Duration, maxEventInMemory int64,
 	exporter Exporter, logger *log.Logger) *DataExporterScheduler {
+	return NewDataExporterSchedulerWithMetrics(ctx, flushInterval, maxEventInMemory, exporter, logger, nil)
+}
+
+// NewDataExporterSchedulerWithMetrics creates a DataExporterScheduler recording the size, the duration
+// and the failures of the exports.
+func NewDataExporterSchedulerWithMetrics(ctx context.Context, flushInterval time.Duration, maxEventInMemory int64,
+	exporter Exporter, logger *log.Logger, recorder metrics.Recorder) *DataExporterScheduler {
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -36,6 +44,7 @@ func NewDataExporterScheduler(ctx context.Context, flushInterval time.Duration,
 		ticker:          time.NewTicker(flushInterval),
 		logger:          logger,
 		ctx:             ctx,
+		metrics:         metrics.OrNoop(recorder),
 	}
 }
 
@@ -59,23 +68,31 @@ type DataExporterScheduler struct {
 	exporter        Exporter
 	logger          *log.Logger
 	ctx             context.Context
+	metrics         metrics.Recorder
 }
 
 // AddEvent allow to add an event to the local cache and to call the exporter if we reach
 // the maximum number of events that can be present in the cache.
 func (dc *DataExporterScheduler) AddEvent(event FeatureEvent) {
+	dc.AddEventCtx(dc.ctx, event)
+}
+
+// AddEventCtx is AddEvent with the context of the evaluation of the flag.
+// A non bulk exporter receives this context, a bulk exporter sends the events of several evaluations
+// together so it receives the context of the scheduler.
+func (dc *DataExporterScheduler) AddEventCtx(ctx context.Context, event FeatureEvent) {
 	dc.mutex.Lock()
 	defer dc.mutex.Unlock()
 
 	if !dc.exporter.IsBulk() {
 		// if we are not in bulk we are directly flushing the data
 		dc.localCache = append(dc.localCache, event)
-		dc.flush()
+		dc.flush(ctx)
 		return
 	}
 
 	if int64(len(dc.localCache)) >= dc.maxEventInCache {
-		dc.flush()
+		dc.flush(dc.ctx)
 	}
 	dc.localCache = append(dc.localCache, event)
 }
@@ -88,7 +105,7 @@ func (dc *DataExporterScheduler) StartDaemon() {
 		case <-dc.ticker.C:
 			// send data and clear local cache
 			dc.mutex.Lock()
-			dc.flush()
+			dc.flush(dc.ctx)
 			dc.mutex.Unlock()
 		case <-dc.daemonChan:
 			// stop the daemon
@@ -105,17 +122,30 @@ func (dc *DataExporterScheduler) Close() {
 
 	// Send the data still in the cache
 	dc.mutex.Lock()
-	dc.flush()
+	dc.flush(dc.ctx)
+	if len(dc.localCache) > 0 {
+		// the last export failed, there is no next export for these events.
+		dc.metrics.RecordDroppedEvents(len(dc.localCache))
+		dc.localCache = make([]FeatureEvent, 0)
+	}
 	dc.mutex.Unlock()
 }
 
 // flush will call the data exporter and clear the cache
 // this method should be always called with a mutex
-func (dc *DataExporterScheduler) flush() {
+// If the export fails, the events are kept for the next export but the oldest ones are dropped
+// when there are more than maxEventInCache events.
+func (dc *DataExporterScheduler) flush(ctx context.Context) {
 	if len(dc.localCache) > 0 {
-		err := dc.exporter.Export(dc.ctx, dc.logger, dc.localCache)
+		start := time.Now()
+		err := dc.exporter.Export(ctx, dc.logger, dc.localCache)
+		dc.metrics.RecordExport(len(dc.localCache), time.Since(start), err)
 		if err != nil {
 			fflog.Printf(dc.logger, "error while exporting data: %v\n", err)
+			if dropped := int64(len(dc.localCache)) - dc.maxEventInCache; dropped > 0 {
+				dc.localCache = dc.localCache[dropped:]
+				dc.metrics.RecordDroppedEvents(int(dropped))
+			}
 			return
 		}
 	}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/thomaspoignant/go-feature-flag/ffuser"
 	"github.com/thomaspoignant/go-feature-flag/internal/exporter"
+	"github.com/thomaspoignant/go-feature-flag/internal/metrics"
 	"github.com/thomaspoignant/go-feature-flag/internal/model"
 	"github.com/thomaspoignant/go-feature-flag/testutils"
 )
@@ -26,7 +27,7 @@ func TestDataExporterScheduler_flushWithTime(t *testing.T) {
 
 	inputEvents := []exporter.FeatureEvent{
 		exporter.NewFeatureEvent(ffuser.NewAnonymousUser("ABCD"), "random-key",
-			&model.FlagData{Percentage: testconvert.Float64(100)}, "YO", model.VariationDefault, false),
+			&model.FlagData{Percentage: testconvert.Float64(100)}, "YO", model.VariationDefault, model.ReasonDefault, false),
 	}
 
 	for _, event := range inputEvents {
@@ -48,7 +49,7 @@ func TestDataExporterScheduler_flushWithNumberOfEvents(t *testing.T) {
 	for i := 0; i <= 100; i++ {
 		inputEvents = append(inputEvents, exporter.NewFeatureEvent(ffuser.NewAnonymousUser("ABCD"),
 			"random-key", &model.FlagData{Percentage: testconvert.Float64(100)},
-			"YO", model.VariationDefault, false))
+			"YO", model.VariationDefault, model.ReasonDefault, false))
 	}
 	for _, event := range inputEvents {
 		dc.AddEvent(event)
@@ -67,7 +68,7 @@ func TestDataExporterScheduler_defaultFlush(t *testing.T) {
 	for i := 0; i <= 100000; i++ {
 		inputEvents = append(inputEvents, exporter.NewFeatureEvent(ffuser.NewAnonymousUser("ABCD"),
 			"random-key", &model.FlagData{Percentage: testconvert.Float64(100)},
-			"YO", model.VariationDefault, false))
+			"YO", model.VariationDefault, model.ReasonDefault, false))
 	}
 	for _, event := range inputEvents {
 		dc.AddEvent(event)
@@ -92,7 +93,7 @@ func TestDataExporterScheduler_exporterReturnError(t *testing.T) {
 	for i := 0; i <= 200; i++ {
 		inputEvents = append(inputEvents, exporter.NewFeatureEvent(ffuser.NewAnonymousUser("ABCD"),
 			"random-key", &model.FlagData{Percentage: testconvert.Float64(100)},
-			"YO", model.VariationDefault, false))
+			"YO", model.VariationDefault, model.ReasonDefault, false))
 	}
 	for _, event := range inputEvents {
 		dc.AddEvent(event)
@@ -104,6 +105,35 @@ func TestDataExporterScheduler_exporterReturnError(t *testing.T) {
 	assert.Regexp(t, "\\["+testutils.RFC3339Regex+"\\] error while exporting data: random err\\n", string(logs))
 }
 
+// droppedEventsRecorder counts the events dropped by the data exporter.
+type droppedEventsRecorder struct {
+	metrics.Noop
+	dropped int
+}
+
+func (r *droppedEventsRecorder) RecordDroppedEvents(count int) {
+	r.dropped += count
+}
+
+func TestDataExporterScheduler_droppedEvents(t *testing.T) {
+	mockExporter := testutils.MockExporter{Err: errors.New("random err"), ExpectedNumberErr: 1000, Bulk: true}
+	recorder := &droppedEventsRecorder{}
+	dc := exporter.NewDataExporterSchedulerWithMetrics(
+		context.Background(), time.Hour, 10, &mockExporter, log.New(ioutil.Discard, "", 0), recorder)
+
+	for i := 0; i < 25; i++ {
+		dc.AddEvent(exporter.NewFeatureEvent(ffuser.NewAnonymousUser("ABCD"),
+			"random-key", &model.FlagData{Percentage: testconvert.Float64(100)},
+			"YO", model.VariationDefault, model.ReasonDefault, false))
+	}
+	// the events kept after the failed exports are limited to the max number of events in memory.
+	assert.Equal(t, 14, recorder.dropped)
+
+	// the events not exported when closing the scheduler are dropped.
+	dc.Close()
+	assert.Equal(t, 25, recorder.dropped)
+}
+
 func TestDataExporterScheduler_nonBulkExporter(t *testing.T) {
 	mockExporter := testutils.MockExporter{Bulk: false}
 	dc := exporter.NewDataExporterScheduler(
@@ -114,7 +144,7 @@ func TestDataExporterScheduler_nonBulkExporter(t *testing.T) {
 	for i := 0; i < 100; i++ {
 		inputEvents = append(inputEvents, exporter.NewFeatureEvent(ffuser.NewAnonymousUser("ABCD"),
 			"random-key", &model.FlagData{Percentage: testconvert.Float64(100)},
-			"YO", model.VariationDefault, false))
+			"YO", model.VariationDefault, model.ReasonDefault, false))
 	}
 	for _, event := range inputEvents {
 		dc.AddEvent(event)
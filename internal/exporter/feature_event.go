@@ -13,6 +13,7 @@ func NewFeatureEvent(
 	flag model.Flag,
 	value interface{},
 	variation model.VariationType,
+	reason model.ResolutionReason,
 	failed bool) FeatureEvent {
 	contextKind := "user"
 	if user.IsAnonymous() {
@@ -26,6 +27,7 @@ func NewFeatureEvent(
 		CreationDate: time.Now().Unix(),
 		Key:          flagKey,
 		Variation:    variation,
+		Reason:       reason,
 		Value:        value,
 		Default:      failed,
 	}
@@ -53,8 +55,12 @@ type FeatureEvent struct {
 	// The variation of the flag requested. Flag variation values can be "True", "False", "Default" or "SdkDefault"
 	// depending on which value was taken during flag evaluation. "SdkDefault" is used when an error is detected and the
 	// default value passed during the call to your variation is used.
+	// If the flag is using named variations, this is the name of the variation served.
 	Variation model.VariationType `json:"variation"`
 
+	// The reason explaining why this variation has been served (ex: "TARGETING_MATCH", "SPLIT", "FLAG_NOT_FOUND" ...).
+	Reason model.ResolutionReason `json:"reason,omitempty"`
+
 	// The value of the feature flag returned by feature flag evaluation.
 	Value interface{} `json:"value"`
 
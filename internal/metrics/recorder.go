@@ -0,0 +1,47 @@
+package metrics
+
+import "time"
+
+// UnknownFlag is the flag key used to record the evaluations of the flags that are not in the cache,
+// the number of values of a metric label has to stay bounded.
+const UnknownFlag = "<unknown>"
+
+// Recorder receives the metrics of go-feature-flag.
+type Recorder interface {
+	// RecordEvaluation is called for every evaluation of a flag.
+	RecordEvaluation(flagKey string, variation string, reason string)
+
+	// RecordRetrieval is called every time the flags are retrieved, err is nil if the retrieval succeeded.
+	RecordRetrieval(duration time.Duration, err error)
+
+	// RecordCacheRefresh is called every time the flags have been retrieved successfully.
+	RecordCacheRefresh(flagCount int, refreshedAt time.Time)
+
+	// RecordExport is called every time the data exporter sends a batch of events.
+	RecordExport(batchSize int, duration time.Duration, err error)
+
+	// RecordDroppedEvents is called when events are dropped without being exported
+	// (the exports keep failing or the last export before closing failed).
+	RecordDroppedEvents(count int)
+
+	// RecordNotifierFailure is called when a notifier fails to send the changes of the flags.
+	RecordNotifierFailure(notifier string)
+}
+
+// Noop is a Recorder doing nothing, it is used when no metrics are configured.
+type Noop struct{}
+
+func (Noop) RecordEvaluation(_ string, _ string, _ string) {}
+func (Noop) RecordRetrieval(_ time.Duration, _ error)      {}
+func (Noop) RecordCacheRefresh(_ int, _ time.Time)         {}
+func (Noop) RecordExport(_ int, _ time.Duration, _ error)  {}
+func (Noop) RecordDroppedEvents(_ int)                     {}
+func (Noop) RecordNotifierFailure(_ string)                {}
+
+// OrNoop returns the recorder, or a Noop recorder if it is nil.
+func OrNoop(recorder Recorder) Recorder {
+	if recorder == nil {
+		return Noop{}
+	}
+	return recorder
+}
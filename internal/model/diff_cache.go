@@ -6,14 +6,37 @@ type DiffCache struct {
 	Deleted map[string]Flag        `json:"deleted"`
 	Added   map[string]Flag        `json:"added"`
 	Updated map[string]DiffUpdated `json:"updated"`
+
+	// Segments contains the changes made in the segments, nil if no segment has changed.
+	Segments *DiffSegments `json:"segments,omitempty"`
 }
 
 // HasDiff check if we have differences
 func (d *DiffCache) HasDiff() bool {
-	return len(d.Deleted) > 0 || len(d.Added) > 0 || len(d.Updated) > 0
+	return len(d.Deleted) > 0 || len(d.Added) > 0 || len(d.Updated) > 0 || d.Segments.HasDiff()
 }
 
 type DiffUpdated struct {
 	Before Flag `json:"old_value"`
 	After  Flag `json:"new_value"`
 }
+
+// DiffSegments contains the changes made in the segments of the flag file.
+type DiffSegments struct {
+	Deleted map[string]Segment            `json:"deleted"`
+	Added   map[string]Segment            `json:"added"`
+	Updated map[string]DiffSegmentUpdated `json:"updated"`
+}
+
+// HasDiff check if we have differences
+func (d *DiffSegments) HasDiff() bool {
+	if d == nil {
+		return false
+	}
+	return len(d.Deleted) > 0 || len(d.Added) > 0 || len(d.Updated) > 0
+}
+
+type DiffSegmentUpdated struct {
+	Before Segment `json:"old_value"`
+	After  Segment `json:"new_value"`
+}
@@ -2,16 +2,17 @@ package model
 
 import (
 	"fmt"
-	"github.com/nikunjy/rules/parser"
 	"hash/fnv"
 	"math"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/thomaspoignant/go-feature-flag/ffuser"
 )
 
-// VariationType enum which describe the decision taken
+// VariationType enum which describe the decision taken.
+// When a flag uses named variations, the VariationType is the name of the variation served.
 type VariationType string
 
 const (
@@ -25,10 +26,15 @@ const (
 const percentageMultiplier = 1000
 
 type Flag interface {
-	// Value is returning the Value associate to the flag (True / False / Default ) based
-	// if the flag apply to the user or not.
+	// Value is returning the Value associate to the flag (True / False / Default or a named variation)
+	// based if the flag apply to the user or not.
 	Value(flagName string, user ffuser.User) (interface{}, VariationType)
 
+	// Evaluate is returning the Value associate to the flag for this user and the details
+	// on how this value has been selected (variation, targeting rule applied).
+	// The flagSet is used to resolve the segments used in the rules, it can be nil.
+	Evaluate(flagName string, user ffuser.User, flagSet FlagSet) (interface{}, ResolutionDetails)
+
 	// String display correctly a flag with the right formatting
 	String() string
 
@@ -60,9 +66,41 @@ type Flag interface {
 	// Default: false
 	GetDisable() bool
 
+	// GetClientSide is the getter of the field ClientSide
+	// Default: false
+	GetClientSide() bool
+
 	// GetRollout is the getter of the field Rollout
 	// Default: nil
 	GetRollout() *Rollout
+
+	// GetVariations is the getter of the field Variations
+	// Default: nil
+	GetVariations() map[string]interface{}
+
+	// GetPercentages is the getter of the field Percentages
+	// Default: nil
+	GetPercentages() map[string]float64
+
+	// GetTargeting is the getter of the field Targeting
+	// Default: nil
+	GetTargeting() []TargetingRule
+
+	// GetPrerequisites is the getter of the field Prerequisites
+	// Default: nil
+	GetPrerequisites() []Prerequisite
+
+	// GetTargets is the getter of the field Targets
+	// Default: nil
+	GetTargets() map[string]string
+
+	// GetBucketBy is the getter of the field BucketBy
+	// Default: empty string (the key of the user is used)
+	GetBucketBy() string
+
+	// GetSalt is the getter of the field Salt
+	// Default: empty string
+	GetSalt() string
 }
 
 // FlagData describe the fields of a flag.
@@ -94,31 +132,196 @@ type FlagData struct {
 	// Disable is true if the flag is disabled.
 	Disable *bool `json:"disable,omitempty" yaml:"disable,omitempty" toml:"disable,omitempty"`
 
+	// ClientSide is true if the flag can be safely exposed to a client side application (browser, mobile ...).
+	// Default value is false
+	ClientSide *bool `json:"clientSide,omitempty" yaml:"clientSide,omitempty" toml:"clientSide,omitempty"`
+
 	// Rollout is the object to configure how the flag is rollout.
 	// You have different rollout strategy available but only one is used at a time.
 	Rollout *Rollout `json:"rollout,omitempty" yaml:"rollout,omitempty" toml:"rollout,omitempty" slack_short:"false"` // nolint: lll
+
+	// Variations are the named values the flag can serve.
+	// When variations are set, the flag is serving one of them (split with Percentages)
+	// to the users matching the rule instead of the True and False values.
+	Variations map[string]interface{} `json:"variations,omitempty" yaml:"variations,omitempty" toml:"variations,omitempty" slack_short:"false"` // nolint: lll
+
+	// Percentages is the split of the users between the named variations, the key is the
+	// name of the variation and the value the percentage of users receiving it.
+	// Users not covered by the split receive the Default value.
+	Percentages map[string]float64 `json:"percentages,omitempty" yaml:"percentages,omitempty" toml:"percentages,omitempty" slack_short:"false"` // nolint: lll
+
+	// Targeting is the ordered list of targeting rules of the flag.
+	// The first rule matching the user is applied, if no rule matches we use the Rule and Percentage of the flag.
+	Targeting []TargetingRule `json:"targeting,omitempty" yaml:"targeting,omitempty" toml:"targeting,omitempty" slack_short:"false"` // nolint: lll
+
+	// Prerequisites are the flags that must serve a specific variation to the user before evaluating this flag.
+	// If one of the prerequisites is not met, the flag serves the Default value.
+	Prerequisites []Prerequisite `json:"prerequisites,omitempty" yaml:"prerequisites,omitempty" toml:"prerequisites,omitempty" slack_short:"false"` // nolint: lll
+
+	// Targets are individual users receiving a specific variation, the key is the key of the user and the value
+	// the name of the variation served. Targets are checked before the targeting rules and the percentages.
+	Targets map[string]string `json:"targets,omitempty" yaml:"targets,omitempty" toml:"targets,omitempty" slack_short:"false"` // nolint: lll
+
+	// BucketBy (optional) is the custom attribute of the user used to select the percentage bucket of the user,
+	// users with the same value receive the same variation. If the user does not have this attribute we use its key.
+	// Default value is the key of the user.
+	BucketBy *string `json:"bucketBy,omitempty" yaml:"bucketBy,omitempty" toml:"bucketBy,omitempty"`
+
+	// Salt (optional) is added to the hash used to select the percentage bucket of the user,
+	// changing it reshuffles the users between the variations without renaming the flag.
+	Salt *string `json:"salt,omitempty" yaml:"salt,omitempty" toml:"salt,omitempty"`
 }
 
 // Value is returning the Value associate to the flag (True / False / Default ) based
 // if the toggle apply to the user or not.
 func (f *FlagData) Value(flagName string, user ffuser.User) (interface{}, VariationType) {
+	value, details := f.Evaluate(flagName, user, nil)
+	return value, details.Variation
+}
+
+// Evaluate is returning the Value associate to the flag for this user and the details
+// on how this value has been selected.
+// The flagSet is used to resolve the segments used in the rules, it can be nil.
+func (f *FlagData) Evaluate(flagName string, user ffuser.User, flagSet FlagSet) (interface{}, ResolutionDetails) {
 	f.updateFlagStage()
 	if f.isExperimentationOver() {
 		// if we have an experimentation that has not started or that is finished we use the default value.
-		return f.GetDefault(), VariationDefault
+		return f.GetDefault(), ResolutionDetails{Variation: VariationDefault, Reason: ReasonExperimentationOver}
+	}
+
+	// Flag disable we cannot apply it.
+	if f.GetDisable() {
+		return f.GetDefault(), ResolutionDetails{Variation: VariationDefault, Reason: ReasonDisabled}
 	}
 
-	if f.evaluateRule(user) {
-		if f.isInPercentage(flagName, user) {
-			// Rule applied and user in the cohort.
-			return f.GetTrue(), VariationTrue
+	// All the prerequisites should be met before evaluating the rules of the flag.
+	for _, prerequisite := range f.Prerequisites {
+		met, err := prerequisite.isMet(flagName, user, flagSet)
+		if err != nil {
+			return f.GetDefault(), ResolutionDetails{Variation: VariationDefault, Reason: ReasonError}
+		}
+		if !met {
+			return f.GetDefault(), ResolutionDetails{Variation: VariationDefault, Reason: ReasonPrerequisiteFailed}
+		}
+	}
+
+	// Individual targets are served before any rule or percentage.
+	if variation, ok := f.Targets[user.GetKey()]; ok {
+		return f.getVariationValue(VariationType(variation)),
+			ResolutionDetails{Variation: VariationType(variation), Reason: ReasonTargetingKey}
+	}
+
+	// Targeting rules are evaluated in order, the first rule matching the user is applied.
+	for index, rule := range f.Targeting {
+		if rule.evaluateQuery(user, flagSet) {
+			variation, split := f.getRuleVariation(flagName, user, rule)
+			return f.getVariationValue(variation), ResolutionDetails{
+				Variation: variation,
+				Reason:    matchReason(split, rule.GetQuery()),
+				RuleName:  rule.GetName(index),
+			}
+		}
+	}
+
+	if f.evaluateRule(user, flagSet) {
+		variation, split := f.getFlagVariation(flagName, user)
+		return f.getVariationValue(variation), ResolutionDetails{
+			Variation: variation,
+			Reason:    matchReason(split, f.GetRule()),
 		}
-		// Rule applied and user not in the cohort.
-		return f.GetFalse(), VariationFalse
 	}
 
 	// Default value is used if the rule does not applied to the user.
-	return f.GetDefault(), VariationDefault
+	return f.GetDefault(), ResolutionDetails{Variation: VariationDefault, Reason: ReasonDefault}
+}
+
+// matchReason returns the reason of an evaluation where the user matched a rule.
+func matchReason(split bool, query string) ResolutionReason {
+	if split {
+		return ReasonSplit
+	}
+	if query != "" {
+		return ReasonTargetingMatch
+	}
+	return ReasonStatic
+}
+
+// getFlagVariation select the variation of a user matching the rule of the flag.
+// It also returns true if the variation has been selected by a percentage split.
+func (f *FlagData) getFlagVariation(flagName string, user ffuser.User) (VariationType, bool) {
+	if f.hasVariations() {
+		// Rule applied, we select the variation based on the split.
+		return f.getSplitVariation(flagName, user, f.Percentages), isSplit(f.Percentages)
+	}
+
+	percentage := f.getActualPercentage()
+	split := percentage > 0 && percentage < 100*percentageMultiplier
+	if f.isInPercentage(flagName, user) {
+		// Rule applied and user in the cohort.
+		return VariationTrue, split
+	}
+	// Rule applied and user not in the cohort.
+	return VariationFalse, split
+}
+
+// getRuleVariation select the variation of a user matching a targeting rule.
+// If the rule does not specify what to serve, we use the variation of the flag.
+// It also returns true if the variation has been selected by a percentage split.
+func (f *FlagData) getRuleVariation(flagName string, user ffuser.User, rule TargetingRule) (VariationType, bool) {
+	switch {
+	case rule.Variation != nil:
+		return VariationType(rule.GetVariation()), false
+	case rule.Percentages != nil:
+		return f.getSplitVariation(flagName, user, rule.Percentages), isSplit(rule.Percentages)
+	case rule.Percentage != nil:
+		split := rule.GetPercentage() > 0 && rule.GetPercentage() < 100
+		if f.bucket(flagName, user) < uint32(rule.GetPercentage()*percentageMultiplier) {
+			return VariationTrue, split
+		}
+		return VariationFalse, split
+	default:
+		return f.getFlagVariation(flagName, user)
+	}
+}
+
+// getSplitVariation select the variation of the user in a split between named variations,
+// if the user is not part of the split we use the default variation.
+func (f *FlagData) getSplitVariation(
+	flagName string, user ffuser.User, percentages map[string]float64) VariationType {
+	if variation, ok := f.getVariationFromPercentages(flagName, user, percentages); ok {
+		return VariationType(variation)
+	}
+	return VariationDefault
+}
+
+// isSplit returns false if the split is serving the same variation to every user.
+func isSplit(percentages map[string]float64) bool {
+	for _, percentage := range percentages {
+		if percentage >= 100 {
+			return false
+		}
+	}
+	return len(percentages) > 0
+}
+
+// getVariationValue returns the value of a variation.
+// For flags without named variations, True, False and Default are the available variations.
+func (f *FlagData) getVariationValue(variation VariationType) interface{} {
+	if f.hasVariations() {
+		if value, ok := f.Variations[string(variation)]; ok {
+			return value
+		}
+		return f.GetDefault()
+	}
+
+	switch variation {
+	case VariationTrue:
+		return f.GetTrue()
+	case VariationFalse:
+		return f.GetFalse()
+	default:
+		return f.GetDefault()
+	}
 }
 
 func (f *FlagData) isExperimentationOver() bool {
@@ -142,12 +345,57 @@ func (f *FlagData) isInPercentage(flagName string, user ffuser.User) bool {
 		return true
 	}
 
-	hashID := Hash(flagName+user.GetKey()) % maxPercentage
-	return hashID < uint32(percentage)
+	return f.bucket(flagName, user) < uint32(percentage)
+}
+
+// getVariationFromPercentages select the named variation of the user based on a split
+// of the users between variations.
+// It returns false if the user is not part of any variation of the split.
+func (f *FlagData) getVariationFromPercentages(
+	flagName string, user ffuser.User, percentages map[string]float64) (string, bool) {
+	// we sort the variations to always have the same split for the same configuration
+	names := make([]string, 0, len(percentages))
+	for name := range percentages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	bucket := f.bucket(flagName, user)
+	upperBound := uint32(0)
+	for _, name := range names {
+		upperBound += uint32(math.Round(percentages[name] * percentageMultiplier))
+		if bucket < upperBound {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// bucket returns the position of the user in the range of possible percentages.
+func (f *FlagData) bucket(flagName string, user ffuser.User) uint32 {
+	return Hash(flagName+f.GetSalt()+f.bucketingValue(user)) % uint32(100*percentageMultiplier)
+}
+
+// bucketingValue returns the value of the user used to select its bucket,
+// it is the attribute BucketBy of the user or its key if the attribute is missing.
+func (f *FlagData) bucketingValue(user ffuser.User) string {
+	if f.GetBucketBy() == "" || f.GetBucketBy() == "key" {
+		return user.GetKey()
+	}
+	value, ok := user.GetCustom()[f.GetBucketBy()]
+	if !ok || value == nil {
+		return user.GetKey()
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// hasVariations returns true if the flag is using named variations.
+func (f *FlagData) hasVariations() bool {
+	return len(f.Variations) > 0
 }
 
 // evaluateRule is checking if the rule can apply to a specific user.
-func (f *FlagData) evaluateRule(user ffuser.User) bool {
+func (f *FlagData) evaluateRule(user ffuser.User, flagSet FlagSet) bool {
 	// Flag disable we cannot apply it.
 	if f.GetDisable() {
 		return false
@@ -159,7 +407,7 @@ func (f *FlagData) evaluateRule(user ffuser.User) bool {
 	}
 
 	// Evaluate the rule on the user.
-	return parser.Evaluate(f.GetRule(), userToMap(user))
+	return evaluateQuery(f.GetRule(), user, flagSet)
 }
 
 // string display correctly a flag
@@ -169,6 +417,25 @@ func (f FlagData) String() string {
 	if f.GetRule() != "" {
 		strBuilder.WriteString(fmt.Sprintf("rule=\"%s\", ", f.GetRule()))
 	}
+	if f.hasVariations() {
+		strBuilder.WriteString(fmt.Sprintf("variations=\"%v\", ", f.Variations))
+		strBuilder.WriteString(fmt.Sprintf("percentages=\"%v\", ", f.Percentages))
+	}
+	if len(f.Targeting) > 0 {
+		strBuilder.WriteString(fmt.Sprintf("targeting=%v, ", f.Targeting))
+	}
+	if len(f.Prerequisites) > 0 {
+		strBuilder.WriteString(fmt.Sprintf("prerequisites=%v, ", f.Prerequisites))
+	}
+	if len(f.Targets) > 0 {
+		strBuilder.WriteString(fmt.Sprintf("targets=%v, ", f.Targets))
+	}
+	if f.BucketBy != nil {
+		strBuilder.WriteString(fmt.Sprintf("bucketBy=\"%s\", ", f.GetBucketBy()))
+	}
+	if f.Salt != nil {
+		strBuilder.WriteString(fmt.Sprintf("salt=\"%s\", ", f.GetSalt()))
+	}
 	strBuilder.WriteString(fmt.Sprintf("true=\"%v\", ", f.GetTrue()))
 	strBuilder.WriteString(fmt.Sprintf("false=\"%v\", ", f.GetFalse()))
 	strBuilder.WriteString(fmt.Sprintf("default=\"%v\", ", f.GetDefault()))
@@ -178,6 +445,10 @@ func (f FlagData) String() string {
 		strBuilder.WriteString(fmt.Sprintf(", trackEvents=\"%v\"", f.GetTrackEvents()))
 	}
 
+	if f.ClientSide != nil {
+		strBuilder.WriteString(fmt.Sprintf(", clientSide=\"%v\"", f.GetClientSide()))
+	}
+
 	return strBuilder.String()
 }
 
@@ -274,6 +545,21 @@ func (f *FlagData) updateFlagStage() {
 	}
 }
 
+// ScheduledVersions returns the flag as it is configured and as it will be after each step of its
+// scheduled rollout, every step is merged with the previous ones like during the evaluation.
+func (f *FlagData) ScheduledVersions() []FlagData {
+	versions := []FlagData{*f}
+	if f.Rollout == nil || f.Rollout.Scheduled == nil {
+		return versions
+	}
+	current := *f
+	for _, step := range f.Rollout.Scheduled.Steps {
+		current.mergeChanges(step)
+		versions = append(versions, current)
+	}
+	return versions
+}
+
 // mergeChanges f;ejs;
 func (f *FlagData) mergeChanges(stepFlag ScheduledStep) {
 	if stepFlag.Disable != nil {
@@ -291,6 +577,9 @@ func (f *FlagData) mergeChanges(stepFlag ScheduledStep) {
 	if stepFlag.TrackEvents != nil {
 		f.TrackEvents = stepFlag.TrackEvents
 	}
+	if stepFlag.ClientSide != nil {
+		f.ClientSide = stepFlag.ClientSide
+	}
 	if stepFlag.Percentage != nil {
 		f.Percentage = stepFlag.Percentage
 	}
@@ -300,6 +589,27 @@ func (f *FlagData) mergeChanges(stepFlag ScheduledStep) {
 	if stepFlag.Rollout != nil {
 		f.Rollout = stepFlag.Rollout
 	}
+	if stepFlag.Variations != nil {
+		f.Variations = stepFlag.Variations
+	}
+	if stepFlag.Percentages != nil {
+		f.Percentages = stepFlag.Percentages
+	}
+	if stepFlag.Targeting != nil {
+		f.Targeting = stepFlag.Targeting
+	}
+	if stepFlag.Prerequisites != nil {
+		f.Prerequisites = stepFlag.Prerequisites
+	}
+	if stepFlag.Targets != nil {
+		f.Targets = stepFlag.Targets
+	}
+	if stepFlag.BucketBy != nil {
+		f.BucketBy = stepFlag.BucketBy
+	}
+	if stepFlag.Salt != nil {
+		f.Salt = stepFlag.Salt
+	}
 }
 
 // GetRule is the getter of the field Rule
@@ -358,7 +668,56 @@ func (f *FlagData) GetDisable() bool {
 	return *f.Disable
 }
 
+// GetClientSide is the getter of the field ClientSide
+func (f *FlagData) GetClientSide() bool {
+	if f.ClientSide == nil {
+		return false
+	}
+	return *f.ClientSide
+}
+
 // GetRollout is the getter of the field Rollout
 func (f *FlagData) GetRollout() *Rollout {
 	return f.Rollout
 }
+
+// GetVariations is the getter of the field Variations
+func (f *FlagData) GetVariations() map[string]interface{} {
+	return f.Variations
+}
+
+// GetPercentages is the getter of the field Percentages
+func (f *FlagData) GetPercentages() map[string]float64 {
+	return f.Percentages
+}
+
+// GetTargeting is the getter of the field Targeting
+func (f *FlagData) GetTargeting() []TargetingRule {
+	return f.Targeting
+}
+
+// GetPrerequisites is the getter of the field Prerequisites
+func (f *FlagData) GetPrerequisites() []Prerequisite {
+	return f.Prerequisites
+}
+
+// GetTargets is the getter of the field Targets
+func (f *FlagData) GetTargets() map[string]string {
+	return f.Targets
+}
+
+// GetBucketBy is the getter of the field BucketBy
+func (f *FlagData) GetBucketBy() string {
+	if f.BucketBy == nil {
+		return ""
+	}
+	return *f.BucketBy
+}
+
+// GetSalt is the getter of the field Salt
+func (f *FlagData) GetSalt() string {
+	if f.Salt == nil {
+		return ""
+	}
+	return *f.Salt
+}
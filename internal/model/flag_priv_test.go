@@ -87,7 +87,7 @@ func TestFlag_evaluateRule(t *testing.T) {
 				False:      testconvert.Interface(tt.fields.False),
 			}
 
-			got := f.evaluateRule(tt.args.user)
+			got := f.evaluateRule(tt.args.user, nil)
 			assert.Equal(t, tt.want, got)
 		})
 	}
@@ -1,6 +1,7 @@
 package model_test
 
 import (
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"testing"
 	"time"
@@ -609,3 +610,414 @@ func TestFlag_Getter(t *testing.T) {
 		})
 	}
 }
+
+func TestFlag_valueWithVariations(t *testing.T) {
+	variations := map[string]interface{}{
+		"control": "control-value",
+		"blue":    "blue-value",
+		"green":   "green-value",
+	}
+	type fields struct {
+		Rule        string
+		Variations  map[string]interface{}
+		Percentages map[string]float64
+		Default     interface{}
+	}
+	type want struct {
+		value         interface{}
+		variationType model.VariationType
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		user   ffuser.User
+		want   want
+	}{
+		{
+			name: "User in the 1st variation of the split",
+			fields: fields{
+				Variations:  variations,
+				Percentages: map[string]float64{"control": 50, "blue": 25, "green": 25},
+				Default:     "default",
+			},
+			user: ffuser.NewUser("user-3"), // bucket is 7642
+			want: want{
+				value:         "blue-value",
+				variationType: "blue",
+			},
+		},
+		{
+			name: "User in the 2nd variation of the split",
+			fields: fields{
+				Variations:  variations,
+				Percentages: map[string]float64{"control": 50, "blue": 25, "green": 25},
+				Default:     "default",
+			},
+			user: ffuser.NewUser("user-1"), // bucket is 52404
+			want: want{
+				value:         "control-value",
+				variationType: "control",
+			},
+		},
+		{
+			name: "User in the last variation of the split",
+			fields: fields{
+				Variations:  variations,
+				Percentages: map[string]float64{"control": 50, "blue": 25, "green": 25},
+				Default:     "default",
+			},
+			user: ffuser.NewUser("user-2"), // bucket is 85261
+			want: want{
+				value:         "green-value",
+				variationType: "green",
+			},
+		},
+		{
+			name: "User not in the split",
+			fields: fields{
+				Variations:  variations,
+				Percentages: map[string]float64{"blue": 20, "green": 20},
+				Default:     "default",
+			},
+			user: ffuser.NewUser("user-5"), // bucket is 41928
+			want: want{
+				value:         "default",
+				variationType: model.VariationDefault,
+			},
+		},
+		{
+			name: "Rule does not apply",
+			fields: fields{
+				Rule:        "key eq \"user-1\"",
+				Variations:  variations,
+				Percentages: map[string]float64{"control": 50, "blue": 25, "green": 25},
+				Default:     "default",
+			},
+			user: ffuser.NewUser("user-3"),
+			want: want{
+				value:         "default",
+				variationType: model.VariationDefault,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &model.FlagData{
+				Rule:        testconvert.String(tt.fields.Rule),
+				Variations:  tt.fields.Variations,
+				Percentages: tt.fields.Percentages,
+				Default:     testconvert.Interface(tt.fields.Default),
+			}
+
+			got, variationType := f.Value("ab-test", tt.user)
+			assert.Equal(t, tt.want.value, got)
+			assert.Equal(t, tt.want.variationType, variationType)
+		})
+	}
+}
+
+func TestFlag_targeting(t *testing.T) {
+	variationsFlag := model.FlagData{
+		Variations: map[string]interface{}{
+			"control": "control-value",
+			"blue":    "blue-value",
+			"green":   "green-value",
+		},
+		Percentages: map[string]float64{"control": 100},
+		Default:     testconvert.Interface("default"),
+		Targeting: []model.TargetingRule{
+			{
+				Name:      testconvert.String("beta-testers"),
+				Query:     testconvert.String("beta eq true"),
+				Variation: testconvert.String("blue"),
+			},
+			{
+				Query:       testconvert.String("country eq \"FR\""),
+				Percentages: map[string]float64{"green": 30},
+			},
+		},
+	}
+	boolFlag := model.FlagData{
+		True:       testconvert.Interface(true),
+		False:      testconvert.Interface(false),
+		Default:    testconvert.Interface(false),
+		Rule:       testconvert.String("key eq \"user-5\""),
+		Percentage: testconvert.Float64(100),
+		Targeting: []model.TargetingRule{
+			{
+				Name:      testconvert.String("qa"),
+				Query:     testconvert.String("qa eq true"),
+				Variation: testconvert.String("True"),
+			},
+			{
+				Name:       testconvert.String("partial"),
+				Query:      testconvert.String("country eq \"FR\""),
+				Percentage: testconvert.Float64(10),
+			},
+		},
+	}
+	tests := []struct {
+		name    string
+		flag    model.FlagData
+		user    ffuser.User
+		want    interface{}
+		details model.ResolutionDetails
+	}{
+		{
+			name:    "First rule matching serve its variation",
+			flag:    variationsFlag,
+			user:    ffuser.NewUserBuilder("user-3").AddCustom("beta", true).AddCustom("country", "FR").Build(),
+			want:    "blue-value",
+			details: model.ResolutionDetails{Variation: "blue", Reason: model.ReasonTargetingMatch, RuleName: "beta-testers"},
+		},
+		{
+			name:    "Second rule matching with user in the split",
+			flag:    variationsFlag,
+			user:    ffuser.NewUserBuilder("user-3").AddCustom("country", "FR").Build(), // bucket is 7642
+			want:    "green-value",
+			details: model.ResolutionDetails{Variation: "green", Reason: model.ReasonSplit, RuleName: "targeting[1]"},
+		},
+		{
+			name:    "Second rule matching with user not in the split",
+			flag:    variationsFlag,
+			user:    ffuser.NewUserBuilder("user-1").AddCustom("country", "FR").Build(), // bucket is 52404
+			want:    "default",
+			details: model.ResolutionDetails{Variation: model.VariationDefault, Reason: model.ReasonSplit, RuleName: "targeting[1]"},
+		},
+		{
+			name:    "No rule matching use the flag split",
+			flag:    variationsFlag,
+			user:    ffuser.NewUserBuilder("user-1").AddCustom("country", "US").Build(),
+			want:    "control-value",
+			details: model.ResolutionDetails{Variation: "control", Reason: model.ReasonStatic},
+		},
+		{
+			name:    "Rule serving True on a boolean flag",
+			flag:    boolFlag,
+			user:    ffuser.NewUserBuilder("user-1").AddCustom("qa", true).Build(),
+			want:    true,
+			details: model.ResolutionDetails{Variation: model.VariationTrue, Reason: model.ReasonTargetingMatch, RuleName: "qa"},
+		},
+		{
+			name:    "Rule with percentage and user in the cohort",
+			flag:    boolFlag,
+			user:    ffuser.NewUserBuilder("user-3").AddCustom("country", "FR").Build(), // bucket is 7642
+			want:    true,
+			details: model.ResolutionDetails{Variation: model.VariationTrue, Reason: model.ReasonSplit, RuleName: "partial"},
+		},
+		{
+			name:    "Rule with percentage and user not in the cohort",
+			flag:    boolFlag,
+			user:    ffuser.NewUserBuilder("user-1").AddCustom("country", "FR").Build(), // bucket is 52404
+			want:    false,
+			details: model.ResolutionDetails{Variation: model.VariationFalse, Reason: model.ReasonSplit, RuleName: "partial"},
+		},
+		{
+			name:    "No rule matching use the flag rule",
+			flag:    boolFlag,
+			user:    ffuser.NewUser("user-5"),
+			want:    true,
+			details: model.ResolutionDetails{Variation: model.VariationTrue, Reason: model.ReasonTargetingMatch},
+		},
+		{
+			name:    "No rule matching and flag rule not matching",
+			flag:    boolFlag,
+			user:    ffuser.NewUser("user-1"),
+			want:    false,
+			details: model.ResolutionDetails{Variation: model.VariationDefault, Reason: model.ReasonDefault},
+		},
+		{
+			name: "Disabled flag ignore the targeting",
+			flag: model.FlagData{
+				Disable:   testconvert.Bool(true),
+				True:      testconvert.Interface("true"),
+				Default:   testconvert.Interface("default"),
+				Targeting: []model.TargetingRule{{Variation: testconvert.String("True")}},
+			},
+			user:    ffuser.NewUser("user-1"),
+			want:    "default",
+			details: model.ResolutionDetails{Variation: model.VariationDefault, Reason: model.ReasonDisabled},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, details := tt.flag.Evaluate("ab-test", tt.user, nil)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.details, details)
+		})
+	}
+}
+
+func TestFlag_targets(t *testing.T) {
+	tests := []struct {
+		name    string
+		flag    model.FlagData
+		user    ffuser.User
+		want    interface{}
+		details model.ResolutionDetails
+	}{
+		{
+			name: "Targeted user is served before the rule and the percentage",
+			flag: model.FlagData{
+				Targets:    map[string]string{"qa-user": "True"},
+				Rule:       testconvert.String("key eq \"other-user\""),
+				Percentage: testconvert.Float64(0),
+				True:       testconvert.Interface("true"),
+				False:      testconvert.Interface("false"),
+				Default:    testconvert.Interface("default"),
+			},
+			user:    ffuser.NewUser("qa-user"),
+			want:    "true",
+			details: model.ResolutionDetails{Variation: model.VariationTrue, Reason: model.ReasonTargetingKey},
+		},
+		{
+			name: "Targeted user forced off",
+			flag: model.FlagData{
+				Targets:    map[string]string{"customer-42": "False"},
+				Percentage: testconvert.Float64(100),
+				True:       testconvert.Interface("true"),
+				False:      testconvert.Interface("false"),
+				Default:    testconvert.Interface("default"),
+			},
+			user:    ffuser.NewUser("customer-42"),
+			want:    "false",
+			details: model.ResolutionDetails{Variation: model.VariationFalse, Reason: model.ReasonTargetingKey},
+		},
+		{
+			name: "Targeted user is served before the targeting rules",
+			flag: model.FlagData{
+				Variations: map[string]interface{}{"blue": "#0000FF", "green": "#00FF00"},
+				Targets:    map[string]string{"qa-user": "blue"},
+				Targeting: []model.TargetingRule{
+					{Variation: testconvert.String("green")},
+				},
+				Default: testconvert.Interface("#FFFFFF"),
+			},
+			user:    ffuser.NewUser("qa-user"),
+			want:    "#0000FF",
+			details: model.ResolutionDetails{Variation: "blue", Reason: model.ReasonTargetingKey},
+		},
+		{
+			name: "User not targeted",
+			flag: model.FlagData{
+				Targets:    map[string]string{"qa-user": "False"},
+				Percentage: testconvert.Float64(100),
+				True:       testconvert.Interface("true"),
+				False:      testconvert.Interface("false"),
+				Default:    testconvert.Interface("default"),
+			},
+			user:    ffuser.NewUser("random-user"),
+			want:    "true",
+			details: model.ResolutionDetails{Variation: model.VariationTrue, Reason: model.ReasonStatic},
+		},
+		{
+			name: "Disabled flag ignores the targets",
+			flag: model.FlagData{
+				Targets: map[string]string{"qa-user": "True"},
+				Disable: testconvert.Bool(true),
+				True:    testconvert.Interface("true"),
+				False:   testconvert.Interface("false"),
+				Default: testconvert.Interface("default"),
+			},
+			user:    ffuser.NewUser("qa-user"),
+			want:    "default",
+			details: model.ResolutionDetails{Variation: model.VariationDefault, Reason: model.ReasonDisabled},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, details := tt.flag.Evaluate("test-flag", tt.user, nil)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.details, details)
+		})
+	}
+}
+
+func TestFlag_bucketByAndSalt(t *testing.T) {
+	// userInPercentage is computing if a bucketing value is part of the 50% of the flag.
+	userInPercentage := func(flagName string, salt string, value string) bool {
+		return model.Hash(flagName+salt+value)%100000 < 50000
+	}
+	newFlag := func(bucketBy *string, salt *string) model.FlagData {
+		return model.FlagData{
+			BucketBy:   bucketBy,
+			Salt:       salt,
+			Percentage: testconvert.Float64(50),
+			True:       testconvert.Interface(true),
+			False:      testconvert.Interface(false),
+			Default:    testconvert.Interface(false),
+		}
+	}
+
+	tests := []struct {
+		name string
+		flag model.FlagData
+		user ffuser.User
+		want bool
+	}{
+		{
+			name: "Bucket by key without salt",
+			flag: newFlag(nil, nil),
+			user: ffuser.NewUser("user-1"),
+			want: userInPercentage("test-flag", "", "user-1"),
+		},
+		{
+			name: "Bucket by custom attribute",
+			flag: newFlag(testconvert.String("companyId"), nil),
+			user: ffuser.NewUserBuilder("user-1").AddCustom("companyId", "acme").Build(),
+			want: userInPercentage("test-flag", "", "acme"),
+		},
+		{
+			name: "Bucket by numeric custom attribute",
+			flag: newFlag(testconvert.String("companyId"), nil),
+			user: ffuser.NewUserBuilder("user-1").AddCustom("companyId", 1234).Build(),
+			want: userInPercentage("test-flag", "", "1234"),
+		},
+		{
+			name: "Bucket by missing attribute falls back to key",
+			flag: newFlag(testconvert.String("companyId"), nil),
+			user: ffuser.NewUser("user-1"),
+			want: userInPercentage("test-flag", "", "user-1"),
+		},
+		{
+			name: "Salt is added to the hash",
+			flag: newFlag(nil, testconvert.String("experiment-2")),
+			user: ffuser.NewUser("user-1"),
+			want: userInPercentage("test-flag", "experiment-2", "user-1"),
+		},
+		{
+			name: "Bucket by custom attribute with salt",
+			flag: newFlag(testconvert.String("companyId"), testconvert.String("experiment-2")),
+			user: ffuser.NewUserBuilder("user-1").AddCustom("companyId", "acme").Build(),
+			want: userInPercentage("test-flag", "experiment-2", "acme"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := tt.flag.Value("test-flag", tt.user)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFlag_bucketBySameCompany(t *testing.T) {
+	flag := model.FlagData{
+		BucketBy: testconvert.String("companyId"),
+		Variations: map[string]interface{}{
+			"A": "A",
+			"B": "B",
+			"C": "C",
+		},
+		Percentages: map[string]float64{"A": 33, "B": 33, "C": 34},
+		Default:     testconvert.Interface("default"),
+	}
+
+	for _, company := range []string{"acme", "globex", "initech", "umbrella"} {
+		first, _ := flag.Value("test-flag", ffuser.NewUserBuilder("user-1").AddCustom("companyId", company).Build())
+		for i := 2; i < 20; i++ {
+			user := ffuser.NewUserBuilder(fmt.Sprintf("user-%d", i)).AddCustom("companyId", company).Build()
+			got, _ := flag.Value("test-flag", user)
+			assert.Equal(t, first, got, "all the users of %s should have the same variation", company)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+package model
+
+// FlagSet gives access to the configuration loaded with a flag, it is used during the evaluation
+// of the flag to resolve the segments used in the rules and the prerequisites.
+type FlagSet interface {
+	// GetFlag returns the flag with this key, an error is returned if the flag does not exist.
+	GetFlag(key string) (Flag, error)
+
+	// GetSegment returns the segment with this name, false if the segment does not exist.
+	GetSegment(name string) (Segment, bool)
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/thomaspoignant/go-feature-flag/ffuser"
+)
+
+// Prerequisite is a flag that must resolve to a specific variation for the same user
+// before the flag depending on it can be evaluated.
+type Prerequisite struct {
+	// Key is the key of the flag used as prerequisite.
+	Key string `json:"key" yaml:"key" toml:"key"`
+
+	// Variation is the name of the variation the prerequisite flag must serve to the user
+	// (ex: True, False, Default or the name of a named variation).
+	Variation string `json:"variation" yaml:"variation" toml:"variation"`
+}
+
+// prerequisitesPath is the FlagSet used to evaluate the prerequisites of a flag, it keeps the keys
+// of the flags being evaluated to stop the evaluation if the prerequisites contain a cycle.
+type prerequisitesPath struct {
+	FlagSet
+	keys []string
+}
+
+// isMet is checking if the prerequisite flag serves the expected variation to the user.
+// An error is returned if the prerequisite depends on the flag being evaluated.
+func (p Prerequisite) isMet(flagName string, user ffuser.User, flagSet FlagSet) (bool, error) {
+	if flagSet == nil {
+		return false, nil
+	}
+	path, ok := flagSet.(prerequisitesPath)
+	if !ok {
+		path = prerequisitesPath{FlagSet: flagSet, keys: []string{flagName}}
+	}
+	for _, key := range path.keys {
+		if key == p.Key {
+			return false, fmt.Errorf("prerequisites cycle detected: %s -> %s", strings.Join(path.keys, " -> "), p.Key)
+		}
+	}
+
+	flag, err := path.GetFlag(p.Key)
+	if err != nil {
+		return false, nil
+	}
+	keys := make([]string, 0, len(path.keys)+1)
+	keys = append(append(keys, path.keys...), p.Key)
+	_, details := flag.Evaluate(p.Key, user, prerequisitesPath{FlagSet: path.FlagSet, keys: keys})
+	if details.Reason == ReasonError {
+		return false, fmt.Errorf("impossible to evaluate the prerequisite %s", p.Key)
+	}
+	return string(details.Variation) == p.Variation, nil
+}
+
+// String display correctly a prerequisite.
+func (p Prerequisite) String() string {
+	return fmt.Sprintf("%s=%s", p.Key, p.Variation)
+}
@@ -0,0 +1,162 @@
+package model_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thomaspoignant/go-feature-flag/ffuser"
+	"github.com/thomaspoignant/go-feature-flag/internal/model"
+	"github.com/thomaspoignant/go-feature-flag/testutils/testconvert"
+)
+
+// flags is a model.FlagSet containing only flags.
+type flags map[string]model.FlagData
+
+func (f flags) GetFlag(key string) (model.Flag, error) {
+	flag, ok := f[key]
+	if !ok {
+		return nil, fmt.Errorf("flag [%v] does not exists", key)
+	}
+	return &flag, nil
+}
+
+func (f flags) GetSegment(name string) (model.Segment, bool) {
+	return model.Segment{}, false
+}
+
+func TestFlag_EvaluateWithPrerequisites(t *testing.T) {
+	flagSet := flags{
+		"new-payment-backend": {
+			Rule:       testconvert.String("key eq \"user-1\""),
+			Percentage: testconvert.Float64(100),
+			True:       testconvert.Interface(true),
+			False:      testconvert.Interface(false),
+			Default:    testconvert.Interface(false),
+		},
+		"disabled-flag": {
+			Percentage: testconvert.Float64(100),
+			True:       testconvert.Interface(true),
+			False:      testconvert.Interface(false),
+			Default:    testconvert.Interface(false),
+			Disable:    testconvert.Bool(true),
+		},
+		"color": {
+			Variations: map[string]interface{}{"blue": "#0000FF", "green": "#00FF00"},
+			Targeting: []model.TargetingRule{
+				{Variation: testconvert.String("green")},
+			},
+			Default: testconvert.Interface("#FFFFFF"),
+		},
+	}
+
+	tests := []struct {
+		name          string
+		prerequisites []model.Prerequisite
+		user          ffuser.User
+		flagSet       model.FlagSet
+		want          interface{}
+		wantReason    model.ResolutionReason
+	}{
+		{
+			name:          "Prerequisite met",
+			prerequisites: []model.Prerequisite{{Key: "new-payment-backend", Variation: "True"}},
+			user:          ffuser.NewUser("user-1"),
+			flagSet:       flagSet,
+			want:          "true",
+			wantReason:    model.ReasonStatic,
+		},
+		{
+			name:          "Prerequisite not met",
+			prerequisites: []model.Prerequisite{{Key: "new-payment-backend", Variation: "True"}},
+			user:          ffuser.NewUser("user-2"),
+			flagSet:       flagSet,
+			want:          "default",
+			wantReason:    model.ReasonPrerequisiteFailed,
+		},
+		{
+			name: "One of the prerequisites not met",
+			prerequisites: []model.Prerequisite{
+				{Key: "new-payment-backend", Variation: "True"},
+				{Key: "color", Variation: "blue"},
+			},
+			user:       ffuser.NewUser("user-1"),
+			flagSet:    flagSet,
+			want:       "default",
+			wantReason: model.ReasonPrerequisiteFailed,
+		},
+		{
+			name: "Named variation prerequisite",
+			prerequisites: []model.Prerequisite{
+				{Key: "color", Variation: "green"},
+			},
+			user:       ffuser.NewUser("user-2"),
+			flagSet:    flagSet,
+			want:       "true",
+			wantReason: model.ReasonStatic,
+		},
+		{
+			name:          "Disabled prerequisite",
+			prerequisites: []model.Prerequisite{{Key: "disabled-flag", Variation: "True"}},
+			user:          ffuser.NewUser("user-1"),
+			flagSet:       flagSet,
+			want:          "default",
+			wantReason:    model.ReasonPrerequisiteFailed,
+		},
+		{
+			name:          "Unknown prerequisite",
+			prerequisites: []model.Prerequisite{{Key: "unknown", Variation: "True"}},
+			user:          ffuser.NewUser("user-1"),
+			flagSet:       flagSet,
+			want:          "default",
+			wantReason:    model.ReasonPrerequisiteFailed,
+		},
+		{
+			name:          "No flagSet",
+			prerequisites: []model.Prerequisite{{Key: "new-payment-backend", Variation: "True"}},
+			user:          ffuser.NewUser("user-1"),
+			flagSet:       nil,
+			want:          "default",
+			wantReason:    model.ReasonPrerequisiteFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flag := model.FlagData{
+				Prerequisites: tt.prerequisites,
+				Percentage:    testconvert.Float64(100),
+				True:          testconvert.Interface("true"),
+				False:         testconvert.Interface("false"),
+				Default:       testconvert.Interface("default"),
+			}
+			got, details := flag.Evaluate("new-checkout-ui", tt.user, tt.flagSet)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantReason, details.Reason)
+		})
+	}
+}
+
+func TestFlag_EvaluateWithPrerequisitesCycle(t *testing.T) {
+	// the cycle is only visible in the cache, the evaluation should stop instead of recursing forever.
+	flagSet := flags{
+		"flag-a": {
+			Prerequisites: []model.Prerequisite{{Key: "flag-b", Variation: "True"}},
+			Percentage:    testconvert.Float64(100),
+			True:          testconvert.Interface(true),
+			False:         testconvert.Interface(false),
+			Default:       testconvert.Interface(false),
+		},
+		"flag-b": {
+			Prerequisites: []model.Prerequisite{{Key: "flag-a", Variation: "True"}},
+			Percentage:    testconvert.Float64(100),
+			True:          testconvert.Interface(true),
+			False:         testconvert.Interface(false),
+			Default:       testconvert.Interface(false),
+		},
+	}
+	flag := flagSet["flag-a"]
+	got, details := flag.Evaluate("flag-a", ffuser.NewUser("user-1"), flagSet)
+	assert.Equal(t, false, got)
+	assert.Equal(t, model.ReasonError, details.Reason)
+}
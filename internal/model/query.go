@@ -0,0 +1,137 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+	"github.com/nikunjy/rules/parser"
+
+	"github.com/thomaspoignant/go-feature-flag/ffuser"
+)
+
+// segmentKeyword is the attribute used in the queries to target a segment (ex: segment eq "beta-testers").
+const segmentKeyword = "segment"
+
+// segmentsAttribute is the attribute added to the user to evaluate the segment comparisons of a query.
+const segmentsAttribute = "goff-segments"
+
+// evaluateQuery is checking if the query apply to the user,
+// the segments used in the query are resolved with the flagSet.
+func evaluateQuery(query string, user ffuser.User, flagSet FlagSet) bool {
+	userMap := userToMap(user)
+	if strings.Contains(query, segmentKeyword) {
+		var segments map[string]interface{}
+		query, segments = resolveSegments(query, user, flagSet)
+		if len(segments) > 0 {
+			userMap[segmentsAttribute] = segments
+		}
+	}
+	return parser.Evaluate(query, userMap)
+}
+
+// resolveSegments replaces each segment comparison of the query (segment eq, segment ne, segment in)
+// by a boolean attribute containing the result of the comparison for this user.
+// It returns the new query and the attributes to add to the user.
+func resolveSegments(query string, user ffuser.User, flagSet FlagSet) (string, map[string]interface{}) {
+	lexer := parser.NewJsonQueryLexer(antlr.NewInputStream(query))
+	lexer.RemoveErrorListeners()
+	tokens := lexer.GetAllTokens()
+
+	// token positions are indexes of runes
+	runes := []rune(query)
+	segments := make(map[string]interface{})
+	var builder strings.Builder
+	last := 0
+	for i := 0; i < len(tokens); i++ {
+		end, names, ok := matchSegmentComparison(tokens, i)
+		if !ok {
+			continue
+		}
+
+		attr := fmt.Sprintf("s%d", len(segments))
+		member := false
+		for _, name := range names {
+			member = member || isSegmentMember(flagSet, name, user)
+		}
+		if tokens[i+2].GetTokenType() == parser.JsonQueryLexerNE {
+			member = !member
+		}
+		segments[attr] = member
+
+		builder.WriteString(string(runes[last:tokens[i].GetStart()]))
+		builder.WriteString(fmt.Sprintf("%s.%s eq true", segmentsAttribute, attr))
+		last = tokens[end].GetStop() + 1
+		i = end
+	}
+	builder.WriteString(string(runes[last:]))
+	return builder.String(), segments
+}
+
+// matchSegmentComparison checks if a segment comparison starts at the index start of the tokens.
+// It returns the index of the last token of the comparison and the names of the segments compared.
+func matchSegmentComparison(tokens []antlr.Token, start int) (int, []string, bool) {
+	if len(tokens) < start+5 ||
+		tokens[start].GetTokenType() != parser.JsonQueryLexerATTRNAME ||
+		tokens[start].GetText() != segmentKeyword ||
+		tokens[start+1].GetTokenType() != parser.JsonQueryLexerSP ||
+		tokens[start+3].GetTokenType() != parser.JsonQueryLexerSP {
+		return 0, nil, false
+	}
+
+	// segment is a nested attribute (ex: user.segment eq "value"), not a segment comparison.
+	if start > 0 && tokens[start-1].GetTokenType() == parser.JsonQueryLexerT__3 {
+		return 0, nil, false
+	}
+
+	switch tokens[start+2].GetTokenType() {
+	case parser.JsonQueryLexerEQ, parser.JsonQueryLexerNE:
+		if tokens[start+4].GetTokenType() != parser.JsonQueryLexerSTRING {
+			return 0, nil, false
+		}
+		return start + 4, []string{unquote(tokens[start+4].GetText())}, true
+
+	case parser.JsonQueryLexerIN:
+		// list of strings: '[' STRING (COMMA STRING)* ']'
+		if tokens[start+4].GetTokenType() != parser.JsonQueryLexerT__5 {
+			return 0, nil, false
+		}
+		names := make([]string, 0)
+		for i := start + 5; i+1 < len(tokens); i += 2 {
+			if tokens[i].GetTokenType() != parser.JsonQueryLexerSTRING {
+				return 0, nil, false
+			}
+			names = append(names, unquote(tokens[i].GetText()))
+			switch tokens[i+1].GetTokenType() {
+			case parser.JsonQueryLexerT__6:
+				return i + 1, names, true
+			case parser.JsonQueryLexerCOMMA:
+				continue
+			default:
+				return 0, nil, false
+			}
+		}
+	}
+	return 0, nil, false
+}
+
+// isSegmentMember is checking if the user is part of the segment, unknown segments have no member.
+func isSegmentMember(flagSet FlagSet, name string, user ffuser.User) bool {
+	if flagSet == nil {
+		return false
+	}
+	segment, ok := flagSet.GetSegment(name)
+	if !ok {
+		return false
+	}
+	return segment.IsMember(user)
+}
+
+// unquote removes the quotes of a string token.
+func unquote(str string) string {
+	if res, err := strconv.Unquote(str); err == nil {
+		return res
+	}
+	return strings.Trim(str, "\"")
+}
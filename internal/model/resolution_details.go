@@ -0,0 +1,61 @@
+package model
+
+// ResolutionReason enum which describe why a value has been served to a user.
+type ResolutionReason string
+
+const (
+	// ReasonTargetingMatch is used when the user matched a rule of the flag.
+	ReasonTargetingMatch ResolutionReason = "TARGETING_MATCH"
+
+	// ReasonTargetingKey is used when the user is one of the individual targets of the flag.
+	ReasonTargetingKey ResolutionReason = "TARGETING_KEY"
+
+	// ReasonSplit is used when the variation has been selected by a percentage split.
+	ReasonSplit ResolutionReason = "SPLIT"
+
+	// ReasonStatic is used when the flag serves the same variation to every user.
+	ReasonStatic ResolutionReason = "STATIC"
+
+	// ReasonDefault is used when no rule of the flag matched the user.
+	ReasonDefault ResolutionReason = "DEFAULT"
+
+	// ReasonDisabled is used when the flag is disabled.
+	ReasonDisabled ResolutionReason = "DISABLED"
+
+	// ReasonPrerequisiteFailed is used when a prerequisite of the flag did not resolve to the expected variation.
+	ReasonPrerequisiteFailed ResolutionReason = "PREREQUISITE_FAILED"
+
+	// ReasonExperimentationOver is used when the flag has an experimentation rollout not running.
+	ReasonExperimentationOver ResolutionReason = "EXPERIMENTATION_OVER"
+
+	// ReasonFlagNotFound is used when the flag does not exist.
+	ReasonFlagNotFound ResolutionReason = "FLAG_NOT_FOUND"
+
+	// ReasonTypeMismatch is used when the value of the flag is not of the type expected.
+	ReasonTypeMismatch ResolutionReason = "TYPE_MISMATCH"
+
+	// ReasonError is used when an unexpected error happened during the evaluation.
+	ReasonError ResolutionReason = "ERROR"
+)
+
+// ErrorCode enum which describe the error that happened during an evaluation.
+type ErrorCode string
+
+const (
+	ErrorCodeFlagNotFound ErrorCode = "FLAG_NOT_FOUND"
+	ErrorCodeTypeMismatch ErrorCode = "TYPE_MISMATCH"
+	ErrorCodeGeneral      ErrorCode = "GENERAL"
+)
+
+// ResolutionDetails describes how the value of a flag has been selected for a user.
+type ResolutionDetails struct {
+	// Variation is the variation served to the user.
+	Variation VariationType
+
+	// Reason explains why this variation has been served.
+	Reason ResolutionReason
+
+	// RuleName is the name of the targeting rule applied to the user,
+	// it is empty if no targeting rule has matched.
+	RuleName string
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nikunjy/rules/parser"
+
+	"github.com/thomaspoignant/go-feature-flag/ffuser"
+)
+
+// Segment is a named group of users defined in the segments section of the flag file.
+// Flags can target a segment in their rules with the query segment eq "<segment name>".
+type Segment struct {
+	// Included is the list of user keys always part of the segment.
+	Included []string `json:"included,omitempty" yaml:"included,omitempty" toml:"included,omitempty"`
+
+	// Excluded is the list of user keys never part of the segment,
+	// a key present in both Included and Excluded is part of the segment.
+	Excluded []string `json:"excluded,omitempty" yaml:"excluded,omitempty" toml:"excluded,omitempty"`
+
+	// Rule (optional) selects the other users part of the segment.
+	// Rule format is based on the nikunjy/rules module.
+	Rule *string `json:"rule,omitempty" yaml:"rule,omitempty" toml:"rule,omitempty"`
+}
+
+// GetRule is the getter of the field Rule
+func (s *Segment) GetRule() string {
+	if s.Rule == nil {
+		return ""
+	}
+	return *s.Rule
+}
+
+// IsMember is checking if the user is part of the segment.
+func (s *Segment) IsMember(user ffuser.User) bool {
+	for _, key := range s.Included {
+		if key == user.GetKey() {
+			return true
+		}
+	}
+
+	for _, key := range s.Excluded {
+		if key == user.GetKey() {
+			return false
+		}
+	}
+
+	// Without rule the segment contains only the included users.
+	if s.GetRule() == "" {
+		return false
+	}
+	return parser.Evaluate(s.GetRule(), userToMap(user))
+}
+
+// String display correctly a segment.
+func (s Segment) String() string {
+	buf := make([]string, 0)
+	if len(s.Included) > 0 {
+		buf = append(buf, fmt.Sprintf("included=%v", s.Included))
+	}
+	if len(s.Excluded) > 0 {
+		buf = append(buf, fmt.Sprintf("excluded=%v", s.Excluded))
+	}
+	if s.Rule != nil {
+		buf = append(buf, fmt.Sprintf("rule=\"%s\"", s.GetRule()))
+	}
+	return strings.Join(buf, ", ")
+}
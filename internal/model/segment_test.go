@@ -0,0 +1,175 @@
+package model_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thomaspoignant/go-feature-flag/ffuser"
+	"github.com/thomaspoignant/go-feature-flag/internal/model"
+	"github.com/thomaspoignant/go-feature-flag/testutils/testconvert"
+)
+
+func TestSegment_IsMember(t *testing.T) {
+	segment := model.Segment{
+		Included: []string{"user-1", "user-3"},
+		Excluded: []string{"user-2", "user-3"},
+		Rule:     testconvert.String("email ew \"@example.com\""),
+	}
+	tests := []struct {
+		name string
+		user ffuser.User
+		want bool
+	}{
+		{
+			name: "Included user",
+			user: ffuser.NewUser("user-1"),
+			want: true,
+		},
+		{
+			name: "Excluded user matching the rule",
+			user: ffuser.NewUserBuilder("user-2").AddCustom("email", "john@example.com").Build(),
+			want: false,
+		},
+		{
+			name: "Included and excluded user",
+			user: ffuser.NewUser("user-3"),
+			want: true,
+		},
+		{
+			name: "User matching the rule",
+			user: ffuser.NewUserBuilder("user-4").AddCustom("email", "jane@example.com").Build(),
+			want: true,
+		},
+		{
+			name: "User not matching the rule",
+			user: ffuser.NewUserBuilder("user-5").AddCustom("email", "jane@gmail.com").Build(),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, segment.IsMember(tt.user))
+		})
+	}
+}
+
+func TestSegment_IsMemberWithoutRule(t *testing.T) {
+	segment := model.Segment{Included: []string{"user-1"}}
+	assert.True(t, segment.IsMember(ffuser.NewUser("user-1")))
+	assert.False(t, segment.IsMember(ffuser.NewUser("user-2")))
+}
+
+// segments is a model.FlagSet containing only segments.
+type segments map[string]model.Segment
+
+func (s segments) GetFlag(key string) (model.Flag, error) {
+	return nil, fmt.Errorf("flag [%v] does not exists", key)
+}
+
+func (s segments) GetSegment(name string) (model.Segment, bool) {
+	segment, ok := s[name]
+	return segment, ok
+}
+
+func TestFlag_EvaluateWithSegments(t *testing.T) {
+	flagSet := segments{
+		"beta-testers": {Included: []string{"user-1", "user-2"}},
+		"employees":    {Rule: testconvert.String("email ew \"@example.com\"")},
+	}
+	tests := []struct {
+		name string
+		rule string
+		user ffuser.User
+		want interface{}
+	}{
+		{
+			name: "Member of the segment",
+			rule: "segment eq \"beta-testers\"",
+			user: ffuser.NewUser("user-1"),
+			want: "true",
+		},
+		{
+			name: "Not member of the segment",
+			rule: "segment eq \"beta-testers\"",
+			user: ffuser.NewUser("user-3"),
+			want: "default",
+		},
+		{
+			name: "Not equal to the segment",
+			rule: "segment ne \"beta-testers\"",
+			user: ffuser.NewUser("user-3"),
+			want: "true",
+		},
+		{
+			name: "Unknown segment",
+			rule: "segment eq \"unknown\"",
+			user: ffuser.NewUser("user-1"),
+			want: "default",
+		},
+		{
+			name: "In a list of segments",
+			rule: "segment in [\"beta-testers\", \"employees\"]",
+			user: ffuser.NewUserBuilder("user-4").AddCustom("email", "jane@example.com").Build(),
+			want: "true",
+		},
+		{
+			name: "Segment combined with other conditions",
+			rule: "(segment eq \"beta-testers\" or segment eq \"employees\") and anonymous eq false",
+			user: ffuser.NewUser("user-2"),
+			want: "true",
+		},
+		{
+			name: "Segment combined with other conditions not matching",
+			rule: "segment eq \"beta-testers\" and key eq \"user-1\"",
+			user: ffuser.NewUser("user-2"),
+			want: "default",
+		},
+		{
+			name: "Nested attribute named segment is not a segment",
+			rule: "company.segment eq \"beta-testers\"",
+			user: ffuser.NewUserBuilder("user-5").
+				AddCustom("company", map[string]interface{}{"segment": "beta-testers"}).Build(),
+			want: "true",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flag := model.FlagData{
+				Rule:       testconvert.String(tt.rule),
+				Percentage: testconvert.Float64(100),
+				True:       testconvert.Interface("true"),
+				False:      testconvert.Interface("false"),
+				Default:    testconvert.Interface("default"),
+			}
+			got, _ := flag.Evaluate("test-flag", tt.user, flagSet)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTargetingRule_EvaluateWithSegments(t *testing.T) {
+	flagSet := segments{"beta-testers": {Included: []string{"user-1"}}}
+	flag := model.FlagData{
+		Targeting: []model.TargetingRule{
+			{
+				Name:      testconvert.String("beta"),
+				Query:     testconvert.String("segment eq \"beta-testers\""),
+				Variation: testconvert.String("True"),
+			},
+		},
+		True:    testconvert.Interface("true"),
+		False:   testconvert.Interface("false"),
+		Default: testconvert.Interface("default"),
+	}
+
+	got, details := flag.Evaluate("test-flag", ffuser.NewUser("user-1"), flagSet)
+	assert.Equal(t, "true", got)
+	assert.Equal(t, "beta", details.RuleName)
+
+	// without flagSet the segment has no member
+	got, details = flag.Evaluate("test-flag", ffuser.NewUser("user-1"), nil)
+	assert.NotEqual(t, "beta", details.RuleName)
+	assert.NotEqual(t, "true", got)
+}
@@ -0,0 +1,94 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/thomaspoignant/go-feature-flag/ffuser"
+)
+
+// TargetingRule is one of the ordered rules of a flag.
+// The first rule matching the user decides which variation the user receives.
+type TargetingRule struct {
+	// Name (optional) is the name of the rule, it is used to know which rule has been applied to a user.
+	// Default: the position of the rule in the targeting list (ex: targeting[0]).
+	Name *string `json:"name,omitempty" yaml:"name,omitempty" toml:"name,omitempty"`
+
+	// Query is used to select on which user the rule should apply.
+	// Query format is based on the nikunjy/rules module.
+	// If no query set, the rule apply to all users.
+	Query *string `json:"query,omitempty" yaml:"query,omitempty" toml:"query,omitempty"`
+
+	// Variation (optional) is the name of the variation served to the users matching the query.
+	// For flags without named variations you can use True, False or Default.
+	Variation *string `json:"variation,omitempty" yaml:"variation,omitempty" toml:"variation,omitempty"`
+
+	// Percentage (optional) of the users matching the query who receive the True value,
+	// the others receive the False value.
+	Percentage *float64 `json:"percentage,omitempty" yaml:"percentage,omitempty" toml:"percentage,omitempty"`
+
+	// Percentages (optional) is the split of the users matching the query between the named variations.
+	Percentages map[string]float64 `json:"percentages,omitempty" yaml:"percentages,omitempty" toml:"percentages,omitempty"` // nolint: lll
+}
+
+// GetName returns the name of the rule, if the rule has no name we use its position.
+func (r *TargetingRule) GetName(index int) string {
+	if r.Name == nil || *r.Name == "" {
+		return fmt.Sprintf("targeting[%d]", index)
+	}
+	return *r.Name
+}
+
+// GetQuery is the getter of the field Query
+func (r *TargetingRule) GetQuery() string {
+	if r.Query == nil {
+		return ""
+	}
+	return *r.Query
+}
+
+// GetVariation is the getter of the field Variation
+func (r *TargetingRule) GetVariation() string {
+	if r.Variation == nil {
+		return ""
+	}
+	return *r.Variation
+}
+
+// GetPercentage is the getter of the field Percentage
+func (r *TargetingRule) GetPercentage() float64 {
+	if r.Percentage == nil {
+		return 0
+	}
+	return *r.Percentage
+}
+
+// evaluateQuery is checking if the rule apply to a specific user.
+func (r *TargetingRule) evaluateQuery(user ffuser.User, flagSet FlagSet) bool {
+	// No query means that all users are impacted.
+	if r.GetQuery() == "" {
+		return true
+	}
+	return evaluateQuery(r.GetQuery(), user, flagSet)
+}
+
+// String display correctly a targeting rule.
+func (r TargetingRule) String() string {
+	buf := make([]string, 0)
+	if r.Name != nil {
+		buf = append(buf, fmt.Sprintf("name=\"%s\"", *r.Name))
+	}
+	if r.Query != nil {
+		buf = append(buf, fmt.Sprintf("query=\"%s\"", r.GetQuery()))
+	}
+	if r.Variation != nil {
+		buf = append(buf, fmt.Sprintf("variation=\"%s\"", r.GetVariation()))
+	}
+	if r.Percentage != nil {
+		buf = append(buf, fmt.Sprintf("percentage=%v%%", r.GetPercentage()))
+	}
+	if r.Percentages != nil {
+		buf = append(buf, fmt.Sprintf("percentages=\"%v\"", r.Percentages))
+	}
+	return strings.Join(buf, ", ")
+}
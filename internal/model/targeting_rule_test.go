@@ -0,0 +1,79 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thomaspoignant/go-feature-flag/internal/model"
+	"github.com/thomaspoignant/go-feature-flag/testutils/testconvert"
+)
+
+func TestTargetingRule_GetName(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  model.TargetingRule
+		index int
+		want  string
+	}{
+		{
+			name:  "Rule with a name",
+			rule:  model.TargetingRule{Name: testconvert.String("beta-testers")},
+			index: 2,
+			want:  "beta-testers",
+		},
+		{
+			name:  "Rule without name",
+			rule:  model.TargetingRule{},
+			index: 2,
+			want:  "targeting[2]",
+		},
+		{
+			name:  "Rule with an empty name",
+			rule:  model.TargetingRule{Name: testconvert.String("")},
+			index: 0,
+			want:  "targeting[0]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.rule.GetName(tt.index))
+		})
+	}
+}
+
+func TestTargetingRule_String(t *testing.T) {
+	tests := []struct {
+		name string
+		rule model.TargetingRule
+		want string
+	}{
+		{
+			name: "All fields",
+			rule: model.TargetingRule{
+				Name:        testconvert.String("beta-testers"),
+				Query:       testconvert.String("beta eq true"),
+				Variation:   testconvert.String("blue"),
+				Percentage:  testconvert.Float64(20),
+				Percentages: map[string]float64{"blue": 50, "green": 50},
+			},
+			want: "name=\"beta-testers\", query=\"beta eq true\", variation=\"blue\", percentage=20%, " +
+				"percentages=\"map[blue:50 green:50]\"",
+		},
+		{
+			name: "Only a query",
+			rule: model.TargetingRule{Query: testconvert.String("key eq \"user-1\"")},
+			want: "query=\"key eq \"user-1\"\"",
+		},
+		{
+			name: "Empty rule",
+			rule: model.TargetingRule{},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.rule.String())
+		})
+	}
+}
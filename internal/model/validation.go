@@ -0,0 +1,304 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+	"github.com/nikunjy/rules/parser"
+)
+
+// ValidationError is a problem found in the configuration of a flag or of a segment.
+type ValidationError struct {
+	// Key is the name of the flag or of the segment.
+	Key string
+
+	// Field is the path of the invalid field (ex: targeting[0].query).
+	Field string
+
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("[%s] %s: %s", e.Key, e.Field, e.Message)
+}
+
+// Validate checks the configuration of the flag and returns all the problems found.
+// The flag is checked as it is configured and after each step of its scheduled rollout,
+// a problem is reported once, in the first version of the flag where it appears.
+func (f *FlagData) Validate(flagName string) []ValidationError {
+	v := validator{key: flagName}
+	reported := make(map[ValidationError]bool)
+	for i, version := range f.ScheduledVersions() {
+		prefix := ""
+		if i > 0 {
+			prefix = fmt.Sprintf("rollout.scheduled.steps[%d].", i-1)
+		}
+		versionValidator := validator{key: flagName}
+		versionValidator.validateVersion(&version)
+		for _, err := range versionValidator.errors {
+			if reported[err] {
+				continue
+			}
+			reported[err] = true
+			err.Field = prefix + err.Field
+			v.errors = append(v.errors, err)
+		}
+	}
+
+	if rollout := f.GetRollout(); rollout != nil {
+		v.validateRollout(rollout)
+	}
+	return v.errors
+}
+
+// HasVariation returns true if the flag can serve this variation, in any step of its scheduled rollout.
+func (f *FlagData) HasVariation(name string) bool {
+	for _, version := range f.ScheduledVersions() {
+		if version.isVariation(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isVariation returns true if the name is one of the variations of the flag,
+// flags without named variations have the variations True, False and Default.
+func (f *FlagData) isVariation(name string) bool {
+	if name == string(VariationDefault) {
+		return true
+	}
+	if f.hasVariations() {
+		_, ok := f.Variations[name]
+		return ok
+	}
+	return name == string(VariationTrue) || name == string(VariationFalse)
+}
+
+// Validate checks the configuration of the segment and returns all the problems found.
+func (s *Segment) Validate(segmentName string) []ValidationError {
+	v := validator{key: segmentName}
+	v.validateQuery("rule", s.GetRule())
+	return v.errors
+}
+
+// validator collects the problems found in the configuration of one flag or segment.
+type validator struct {
+	key    string
+	errors []ValidationError
+}
+
+func (v *validator) addError(field string, format string, a ...interface{}) {
+	v.errors = append(v.errors, ValidationError{Key: v.key, Field: field, Message: fmt.Sprintf(format, a...)})
+}
+
+// validateVersion checks one version of the flag, the scheduled steps already merged in it.
+func (v *validator) validateVersion(f *FlagData) {
+	v.validateQuery("rule", f.GetRule())
+	if f.Percentage != nil {
+		v.validatePercentage("percentage", f.GetPercentage())
+	}
+	v.validatePercentages("percentages", f.Percentages)
+	v.validateVariationNames(f, "percentages", f.Percentages)
+	v.validateValueTypes(f)
+
+	for i, rule := range f.GetTargeting() {
+		field := fmt.Sprintf("targeting[%d]", i)
+		v.validateQuery(field+".query", rule.GetQuery())
+		if rule.Variation != nil {
+			v.validateVariationName(f, field+".variation", rule.GetVariation())
+		}
+		if rule.Percentage != nil {
+			v.validatePercentage(field+".percentage", rule.GetPercentage())
+		}
+		v.validatePercentages(field+".percentages", rule.Percentages)
+		v.validateVariationNames(f, field+".percentages", rule.Percentages)
+	}
+
+	users := make([]string, 0, len(f.Targets))
+	for user := range f.Targets {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+	for _, user := range users {
+		v.validateVariationName(f, fmt.Sprintf("targets.%s", user), f.Targets[user])
+	}
+
+	for i, prerequisite := range f.GetPrerequisites() {
+		if prerequisite.Key == "" {
+			v.addError(fmt.Sprintf("prerequisites[%d].key", i), "key is mandatory")
+		}
+	}
+}
+
+// validateVariationName checks that the variation is one of the variations of the flag.
+func (v *validator) validateVariationName(f *FlagData, field string, name string) {
+	if !f.isVariation(name) {
+		v.addError(field, "unknown variation %q", name)
+	}
+}
+
+// validateVariationNames checks that the percentages are splitting the users between variations of the flag.
+func (v *validator) validateVariationNames(f *FlagData, field string, percentages map[string]float64) {
+	for _, name := range sortedKeys(percentages) {
+		v.validateVariationName(f, fmt.Sprintf("%s.%s", field, name), name)
+	}
+}
+
+func (v *validator) validateRollout(rollout *Rollout) {
+	if e := rollout.Experimentation; e != nil && e.Start != nil && e.End != nil && !e.Start.Before(*e.End) {
+		v.addError("rollout.experimentation", "start date should be before end date")
+	}
+
+	if p := rollout.Progressive; p != nil {
+		ramp := p.ReleaseRamp
+		if ramp.Start != nil && ramp.End != nil && !ramp.Start.Before(*ramp.End) {
+			v.addError("rollout.progressive.releaseRamp", "start date should be before end date")
+		}
+		v.validatePercentage("rollout.progressive.percentage.initial", p.Percentage.Initial)
+		v.validatePercentage("rollout.progressive.percentage.end", p.Percentage.End)
+	}
+
+	if s := rollout.Scheduled; s != nil {
+		for i, step := range s.Steps {
+			field := fmt.Sprintf("rollout.scheduled.steps[%d]", i)
+			if step.Date == nil {
+				v.addError(field+".date", "date is mandatory")
+				continue
+			}
+			if i > 0 && s.Steps[i-1].Date != nil && !s.Steps[i-1].Date.Before(*step.Date) {
+				v.addError(field+".date", "steps should be ordered by date")
+			}
+		}
+	}
+}
+
+// validateQuery checks that the query can be parsed by nikunjy/rules.
+func (v *validator) validateQuery(field string, query string) {
+	if query == "" {
+		return
+	}
+	if err := checkQuerySyntax(query); err != nil {
+		v.addError(field, "invalid query %q: %v", query, err)
+	}
+}
+
+func (v *validator) validatePercentage(field string, percentage float64) {
+	if percentage < 0 || percentage > 100 {
+		v.addError(field, "percentage should be between 0 and 100, got %v", percentage)
+	}
+}
+
+func (v *validator) validatePercentages(field string, percentages map[string]float64) {
+	total := 0.0
+	for _, name := range sortedKeys(percentages) {
+		v.validatePercentage(fmt.Sprintf("%s.%s", field, name), percentages[name])
+		total += percentages[name]
+	}
+	if total > 100 {
+		v.addError(field, "the sum of the percentages should not be more than 100, got %v", total)
+	}
+}
+
+// validateValueTypes checks that all the values the flag can serve have the same type.
+func (v *validator) validateValueTypes(f *FlagData) {
+	values := map[string]interface{}{
+		"true":    f.GetTrue(),
+		"false":   f.GetFalse(),
+		"default": f.GetDefault(),
+	}
+	for name, value := range f.GetVariations() {
+		values["variations."+name] = value
+	}
+
+	fields := make([]string, 0, len(values))
+	for field := range values {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	// the default value is the reference type because it is always served
+	referenceField := "default"
+	referenceType := valueType(values[referenceField])
+	for _, field := range fields {
+		if referenceType == "" {
+			referenceField, referenceType = field, valueType(values[field])
+			continue
+		}
+		if t := valueType(values[field]); t != "" && t != referenceType {
+			v.addError(field, "type %s is different from the type of %s (%s)", t, referenceField, referenceType)
+		}
+	}
+}
+
+// valueType returns a name for the type of value, all the numbers have the same type.
+// It returns an empty string for nil.
+func valueType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return ""
+	case bool:
+		return "bool"
+	case string:
+		return "string"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}, map[interface{}]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// queryErrorListener collects the syntax errors of a query.
+type queryErrorListener struct {
+	*antlr.DefaultErrorListener
+	err error
+}
+
+func (l *queryErrorListener) SyntaxError(_ antlr.Recognizer, _ interface{}, _, column int, msg string,
+	_ antlr.RecognitionException) {
+	if l.err == nil {
+		l.err = fmt.Errorf("position %d: %s", column, msg)
+	}
+}
+
+// checkQuerySyntax parses the query with the nikunjy/rules grammar and returns the first syntax error.
+func checkQuerySyntax(query string) (err error) {
+	// antlr lib has panics for exceptions
+	defer func() {
+		if info := recover(); info != nil {
+			err = fmt.Errorf("%v", info)
+		}
+	}()
+
+	listener := &queryErrorListener{DefaultErrorListener: antlr.NewDefaultErrorListener()}
+	lexer := parser.NewJsonQueryLexer(antlr.NewInputStream(query))
+	lexer.RemoveErrorListeners()
+	lexer.AddErrorListener(listener)
+	tokens := antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel)
+	p := parser.NewJsonQueryParser(tokens)
+	p.RemoveErrorListeners()
+	p.AddErrorListener(listener)
+	p.Query()
+
+	if listener.err != nil {
+		return listener.err
+	}
+	// the grammar does not end with EOF, we check that the whole query has been read
+	if next := tokens.LT(1); next != nil && next.GetTokenType() != antlr.TokenEOF {
+		return fmt.Errorf("position %d: unexpected %q", next.GetColumn(), next.GetText())
+	}
+	return nil
+}
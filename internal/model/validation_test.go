@@ -0,0 +1,201 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thomaspoignant/go-feature-flag/internal/model"
+	"github.com/thomaspoignant/go-feature-flag/testutils/testconvert"
+)
+
+func TestFlagData_Validate(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		flag model.FlagData
+		want []model.ValidationError
+	}{
+		{
+			name: "Valid flag",
+			flag: model.FlagData{
+				Rule:       testconvert.String(`key eq "random-key" and (company in ["a", "b"] or beta eq true)`),
+				Percentage: testconvert.Float64(50),
+				True:       testconvert.Interface(1),
+				False:      testconvert.Interface(1.5),
+				Default:    testconvert.Interface(0),
+			},
+		},
+		{
+			name: "Invalid rule",
+			flag: model.FlagData{
+				Rule:    testconvert.String(`key eq "random-key" and`),
+				True:    testconvert.Interface(true),
+				Default: testconvert.Interface(false),
+			},
+			want: []model.ValidationError{
+				{Key: "test-flag", Field: "rule",
+					Message: `invalid query "key eq \"random-key\" and": position 23: mismatched input '<EOF>' expecting SP`},
+			},
+		},
+		{
+			name: "Rule with unexpected end",
+			flag: model.FlagData{
+				Rule: testconvert.String(`key eq "random-key")`),
+			},
+			want: []model.ValidationError{
+				{Key: "test-flag", Field: "rule",
+					Message: `invalid query "key eq \"random-key\")": position 19: unexpected ")"`},
+			},
+		},
+		{
+			name: "Percentage out of range",
+			flag: model.FlagData{
+				Percentage: testconvert.Float64(105),
+				Variations: map[string]interface{}{"A": "a", "B": "b"},
+				Percentages: map[string]float64{
+					"A": 80,
+					"B": 30,
+				},
+			},
+			want: []model.ValidationError{
+				{Key: "test-flag", Field: "percentage", Message: "percentage should be between 0 and 100, got 105"},
+				{Key: "test-flag", Field: "percentages",
+					Message: "the sum of the percentages should not be more than 100, got 110"},
+			},
+		},
+		{
+			name: "Different types",
+			flag: model.FlagData{
+				True:       testconvert.Interface("true"),
+				False:      testconvert.Interface(false),
+				Default:    testconvert.Interface(false),
+				Variations: map[string]interface{}{"A": []interface{}{"a"}},
+			},
+			want: []model.ValidationError{
+				{Key: "test-flag", Field: "true", Message: "type string is different from the type of default (bool)"},
+				{Key: "test-flag", Field: "variations.A",
+					Message: "type array is different from the type of default (bool)"},
+			},
+		},
+		{
+			name: "Invalid targeting",
+			flag: model.FlagData{
+				Targeting: []model.TargetingRule{
+					{Query: testconvert.String(`key eq`), Percentage: testconvert.Float64(-1)},
+				},
+			},
+			want: []model.ValidationError{
+				{Key: "test-flag", Field: "targeting[0].query",
+					Message: `invalid query "key eq": position 6: mismatched input '<EOF>' expecting SP`},
+				{Key: "test-flag", Field: "targeting[0].percentage",
+					Message: "percentage should be between 0 and 100, got -1"},
+			},
+		},
+		{
+			name: "Unknown variations",
+			flag: model.FlagData{
+				Variations:  map[string]interface{}{"A": "a", "B": "b"},
+				Percentages: map[string]float64{"A": 50, "C": 50},
+				Targeting: []model.TargetingRule{
+					{Query: testconvert.String(`beta eq true`), Variation: testconvert.String("True")},
+				},
+				Targets: map[string]string{"user-1": "B", "user-2": "D"},
+				Default: testconvert.Interface("a"),
+			},
+			want: []model.ValidationError{
+				{Key: "test-flag", Field: "percentages.C", Message: `unknown variation "C"`},
+				{Key: "test-flag", Field: "targeting[0].variation", Message: `unknown variation "True"`},
+				{Key: "test-flag", Field: "targets.user-2", Message: `unknown variation "D"`},
+			},
+		},
+		{
+			name: "Invalid scheduled steps",
+			flag: model.FlagData{
+				Rule:    testconvert.String(`key eq`),
+				True:    testconvert.Interface(true),
+				False:   testconvert.Interface(false),
+				Default: testconvert.Interface(false),
+				Rollout: &model.Rollout{
+					Scheduled: &model.ScheduledRollout{
+						Steps: []model.ScheduledStep{
+							{
+								FlagData: model.FlagData{
+									Targeting: []model.TargetingRule{{Query: testconvert.String(`beta eq`)}},
+									Targets:   map[string]string{"user-1": "A"},
+								},
+								Date: testconvert.Time(now),
+							},
+							{
+								FlagData: model.FlagData{
+									Variations: map[string]interface{}{"A": true},
+									True:       testconvert.Interface("true"),
+								},
+								Date: testconvert.Time(now.Add(time.Hour)),
+							},
+						},
+					},
+				},
+			},
+			want: []model.ValidationError{
+				{Key: "test-flag", Field: "rule",
+					Message: `invalid query "key eq": position 6: mismatched input '<EOF>' expecting SP`},
+				{Key: "test-flag", Field: "rollout.scheduled.steps[0].targeting[0].query",
+					Message: `invalid query "beta eq": position 7: mismatched input '<EOF>' expecting SP`},
+				{Key: "test-flag", Field: "rollout.scheduled.steps[0].targets.user-1",
+					Message: `unknown variation "A"`},
+				{Key: "test-flag", Field: "rollout.scheduled.steps[1].true",
+					Message: "type string is different from the type of default (bool)"},
+			},
+		},
+		{
+			name: "Invalid rollout dates",
+			flag: model.FlagData{
+				Rollout: &model.Rollout{
+					Experimentation: &model.Experimentation{
+						Start: testconvert.Time(now),
+						End:   testconvert.Time(now.Add(-1 * time.Hour)),
+					},
+					Progressive: &model.Progressive{
+						ReleaseRamp: model.ProgressiveReleaseRamp{
+							Start: testconvert.Time(now),
+							End:   testconvert.Time(now),
+						},
+					},
+					Scheduled: &model.ScheduledRollout{
+						Steps: []model.ScheduledStep{
+							{Date: testconvert.Time(now)},
+							{Date: testconvert.Time(now.Add(-1 * time.Hour))},
+							{FlagData: model.FlagData{Percentage: testconvert.Float64(50)}},
+						},
+					},
+				},
+			},
+			want: []model.ValidationError{
+				{Key: "test-flag", Field: "rollout.experimentation", Message: "start date should be before end date"},
+				{Key: "test-flag", Field: "rollout.progressive.releaseRamp",
+					Message: "start date should be before end date"},
+				{Key: "test-flag", Field: "rollout.scheduled.steps[1].date",
+					Message: "steps should be ordered by date"},
+				{Key: "test-flag", Field: "rollout.scheduled.steps[2].date", Message: "date is mandatory"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.flag.Validate("test-flag")
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSegment_Validate(t *testing.T) {
+	valid := model.Segment{Rule: testconvert.String(`company eq "go-feature-flag"`)}
+	assert.Empty(t, valid.Validate("segment"))
+
+	invalid := model.Segment{Rule: testconvert.String(`company eq`)}
+	errs := invalid.Validate("segment")
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "rule", errs[0].Field)
+}
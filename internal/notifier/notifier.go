@@ -7,5 +7,6 @@ import (
 )
 
 type Notifier interface {
-	Notify(cache model.DiffCache, waitGroup *sync.WaitGroup)
+	// Notify sends the changes of the flags, the error is returned to count the failures.
+	Notify(cache model.DiffCache, waitGroup *sync.WaitGroup) error
 }
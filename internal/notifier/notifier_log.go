@@ -12,7 +12,7 @@ type LogNotifier struct {
 	Logger *log.Logger
 }
 
-func (c *LogNotifier) Notify(diff model.DiffCache, wg *sync.WaitGroup) {
+func (c *LogNotifier) Notify(diff model.DiffCache, wg *sync.WaitGroup) error {
 	defer wg.Done()
 	for key := range diff.Deleted {
 		fflog.Printf(c.Logger, "flag %v removed\n", key)
@@ -35,4 +35,21 @@ func (c *LogNotifier) Notify(diff model.DiffCache, wg *sync.WaitGroup) {
 		// key has changed in cache
 		fflog.Printf(c.Logger, "flag %s updated, old=[%v], new=[%v]\n", key, flagDiff.Before, flagDiff.After)
 	}
+
+	if diff.Segments == nil {
+		return nil
+	}
+
+	for key := range diff.Segments.Deleted {
+		fflog.Printf(c.Logger, "segment %v removed\n", key)
+	}
+
+	for key := range diff.Segments.Added {
+		fflog.Printf(c.Logger, "segment %v added\n", key)
+	}
+
+	for key, segmentDiff := range diff.Segments.Updated {
+		fflog.Printf(c.Logger, "segment %s updated, old=[%v], new=[%v]\n", key, segmentDiff.Before, segmentDiff.After)
+	}
+	return nil
 }
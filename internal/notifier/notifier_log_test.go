@@ -150,6 +150,28 @@ func TestLogNotifier_Notify(t *testing.T) {
 			},
 			expected: "^\\[" + testutils.RFC3339Regex + "\\] flag test-flag is turned ON \\(flag=\\[percentage=100%, rule=\"key eq \"random-key\"\", true=\"true\", false=\"false\", default=\"false\", disable=\"false\"\\]\\)",
 		},
+		{
+			name: "Update segment",
+			args: args{
+				diff: model.DiffCache{
+					Deleted: map[string]model.Flag{},
+					Updated: map[string]model.DiffUpdated{},
+					Added:   map[string]model.Flag{},
+					Segments: &model.DiffSegments{
+						Deleted: map[string]model.Segment{},
+						Added:   map[string]model.Segment{},
+						Updated: map[string]model.DiffSegmentUpdated{
+							"beta-testers": {
+								Before: model.Segment{Included: []string{"user-1"}},
+								After:  model.Segment{Included: []string{"user-1", "user-2"}},
+							},
+						},
+					},
+				},
+				wg: &sync.WaitGroup{},
+			},
+			expected: "^\\[" + testutils.RFC3339Regex + "\\] segment beta-testers updated, old=\\[included=\\[user-1\\]\\], new=\\[included=\\[user-1 user-2\\]\\]",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
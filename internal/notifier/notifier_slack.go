@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"reflect"
 	"sync"
 
 	"github.com/thomaspoignant/go-feature-flag/internal"
@@ -37,14 +38,14 @@ type SlackNotifier struct {
 	WebhookURL url.URL
 }
 
-func (c *SlackNotifier) Notify(diff model.DiffCache, wg *sync.WaitGroup) {
+func (c *SlackNotifier) Notify(diff model.DiffCache, wg *sync.WaitGroup) error {
 	defer wg.Done()
 
 	reqBody := convertToSlackMessage(diff)
 	payload, err := json.Marshal(reqBody)
 	if err != nil {
 		fflog.Printf(c.Logger, "error: (SlackNotifier) impossible to read differences; %v\n", err)
-		return
+		return err
 	}
 	request := http.Request{
 		Method: http.MethodPost,
@@ -55,15 +56,16 @@ func (c *SlackNotifier) Notify(diff model.DiffCache, wg *sync.WaitGroup) {
 	response, err := c.HTTPClient.Do(&request)
 	if err != nil {
 		fflog.Printf(c.Logger, "error: (SlackNotifier) error: while calling webhook: %v\n", err)
-		return
+		return err
 	}
 
 	defer response.Body.Close()
 	if response.StatusCode > 399 {
 		fflog.Printf(c.Logger, "error: (SlackNotifier) while calling slack webhook, statusCode = %d",
 			response.StatusCode)
-		return
+		return fmt.Errorf("slack webhook returned statusCode %d", response.StatusCode)
 	}
+	return nil
 }
 
 func convertToSlackMessage(diff model.DiffCache) slackMessage {
@@ -71,6 +73,7 @@ func convertToSlackMessage(diff model.DiffCache) slackMessage {
 	attachments := convertDeletedFlagsToSlackMessage(diff)
 	attachments = append(attachments, convertUpdatedFlagsToSlackMessage(diff)...)
 	attachments = append(attachments, convertAddedFlagsToSlackMessage(diff)...)
+	attachments = append(attachments, convertSegmentsToSlackMessage(diff.Segments)...)
 	res := slackMessage{
 		Text:        fmt.Sprintf("Changes detected in your feature flag file on: *%s*", hostname),
 		IconURL:     goFFLogo,
@@ -151,16 +154,73 @@ func convertUpdatedFlagsToSlackMessage(diff model.DiffCache) []attachment {
 				Value: fmt.Sprintf(compareFormat, before.GetDisable(), after.GetDisable())})
 		}
 
+		// ClientSide
+		if before.GetClientSide() != after.GetClientSide() {
+			attachment.Fields = append(attachment.Fields, Field{Title: "ClientSide", Short: true,
+				Value: fmt.Sprintf(compareFormat, before.GetClientSide(), after.GetClientSide())})
+		}
+
 		// Rollout
 		if before.GetRollout() != after.GetRollout() {
 			attachment.Fields = append(attachment.Fields, Field{Title: "Rollout", Short: false,
 				Value: fmt.Sprintf(compareFormat, before.GetRollout(), after.GetRollout())})
 		}
+
+		attachment.Fields = append(attachment.Fields, compareTargetingFields(before, after)...)
 		attachments = append(attachments, attachment)
 	}
 	return attachments
 }
 
+// compareTargetingFields returns the slack fields of the targeting configuration that changed in a flag.
+func compareTargetingFields(before model.Flag, after model.Flag) []Field {
+	const compareFormat = "%v => %v"
+	fields := make([]Field, 0)
+
+	// Variations
+	if !reflect.DeepEqual(before.GetVariations(), after.GetVariations()) {
+		fields = append(fields, Field{Title: "Variations", Short: false,
+			Value: fmt.Sprintf(compareFormat, before.GetVariations(), after.GetVariations())})
+	}
+
+	// Percentages
+	if !reflect.DeepEqual(before.GetPercentages(), after.GetPercentages()) {
+		fields = append(fields, Field{Title: "Percentages", Short: false,
+			Value: fmt.Sprintf(compareFormat, before.GetPercentages(), after.GetPercentages())})
+	}
+
+	// Targeting
+	if !reflect.DeepEqual(before.GetTargeting(), after.GetTargeting()) {
+		fields = append(fields, Field{Title: "Targeting", Short: false,
+			Value: fmt.Sprintf(compareFormat, before.GetTargeting(), after.GetTargeting())})
+	}
+
+	// Prerequisites
+	if !reflect.DeepEqual(before.GetPrerequisites(), after.GetPrerequisites()) {
+		fields = append(fields, Field{Title: "Prerequisites", Short: false,
+			Value: fmt.Sprintf(compareFormat, before.GetPrerequisites(), after.GetPrerequisites())})
+	}
+
+	// Targets
+	if !reflect.DeepEqual(before.GetTargets(), after.GetTargets()) {
+		fields = append(fields, Field{Title: "Targets", Short: false,
+			Value: fmt.Sprintf(compareFormat, before.GetTargets(), after.GetTargets())})
+	}
+
+	// BucketBy
+	if before.GetBucketBy() != after.GetBucketBy() {
+		fields = append(fields, Field{Title: "BucketBy", Short: true,
+			Value: fmt.Sprintf(compareFormat, before.GetBucketBy(), after.GetBucketBy())})
+	}
+
+	// Salt
+	if before.GetSalt() != after.GetSalt() {
+		fields = append(fields, Field{Title: "Salt", Short: true,
+			Value: fmt.Sprintf(compareFormat, before.GetSalt(), after.GetSalt())})
+	}
+	return fields
+}
+
 func convertAddedFlagsToSlackMessage(diff model.DiffCache) []attachment {
 	var attachments = make([]attachment, 0)
 	for key, value := range diff.Added {
@@ -186,11 +246,75 @@ func convertAddedFlagsToSlackMessage(diff model.DiffCache) []attachment {
 			Value: fmt.Sprintf("%v", value.GetTrackEvents())})
 		attachment.Fields = append(attachment.Fields, Field{Title: "Disable", Short: true,
 			Value: fmt.Sprintf("%v", value.GetDisable())})
+		if len(value.GetVariations()) > 0 {
+			attachment.Fields = append(attachment.Fields, Field{Title: "Variations", Short: false,
+				Value: fmt.Sprintf("%v", value.GetVariations())})
+			attachment.Fields = append(attachment.Fields, Field{Title: "Percentages", Short: false,
+				Value: fmt.Sprintf("%v", value.GetPercentages())})
+		}
+		if len(value.GetTargeting()) > 0 {
+			attachment.Fields = append(attachment.Fields, Field{Title: "Targeting", Short: false,
+				Value: fmt.Sprintf("%v", value.GetTargeting())})
+		}
+		if len(value.GetPrerequisites()) > 0 {
+			attachment.Fields = append(attachment.Fields, Field{Title: "Prerequisites", Short: false,
+				Value: fmt.Sprintf("%v", value.GetPrerequisites())})
+		}
+		if len(value.GetTargets()) > 0 {
+			attachment.Fields = append(attachment.Fields, Field{Title: "Targets", Short: false,
+				Value: fmt.Sprintf("%v", value.GetTargets())})
+		}
+		if value.GetBucketBy() != "" {
+			attachment.Fields = append(attachment.Fields, Field{Title: "BucketBy", Short: true,
+				Value: value.GetBucketBy()})
+		}
+		if value.GetSalt() != "" {
+			attachment.Fields = append(attachment.Fields, Field{Title: "Salt", Short: true,
+				Value: value.GetSalt()})
+		}
 		attachments = append(attachments, attachment)
 	}
 	return attachments
 }
 
+func convertSegmentsToSlackMessage(diff *model.DiffSegments) []attachment {
+	var attachments = make([]attachment, 0)
+	if diff == nil {
+		return attachments
+	}
+
+	for key := range diff.Deleted {
+		attachments = append(attachments, attachment{
+			Title:      fmt.Sprintf("❌ Segment \"%s\" deleted", key),
+			Color:      colorDeleted,
+			FooterIcon: goFFLogo,
+			Footer:     slackFooter,
+		})
+	}
+
+	for key, value := range diff.Updated {
+		attachments = append(attachments, attachment{
+			Title:      fmt.Sprintf("✏️ Segment \"%s\" updated", key),
+			Color:      colorUpdated,
+			FooterIcon: goFFLogo,
+			Footer:     slackFooter,
+			Fields: []Field{{Title: "Segment", Short: false,
+				Value: fmt.Sprintf("%v => %v", value.Before, value.After)}},
+		})
+	}
+
+	for key, value := range diff.Added {
+		attachments = append(attachments, attachment{
+			Title:      fmt.Sprintf("🆕 Segment \"%s\" created", key),
+			Color:      colorAdded,
+			FooterIcon: goFFLogo,
+			Footer:     slackFooter,
+			Fields:     []Field{{Title: "Segment", Short: false, Value: fmt.Sprintf("%v", value)}},
+		})
+	}
+	return attachments
+}
+
 type slackMessage struct {
 	IconURL     string       `json:"icon_url"`
 	Text        string       `json:"text"`
@@ -3,6 +3,7 @@ package notifier
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
@@ -61,7 +62,7 @@ type WebhookNotifier struct {
 	Meta        map[string]string
 }
 
-func (c *WebhookNotifier) Notify(diff model.DiffCache, wg *sync.WaitGroup) {
+func (c *WebhookNotifier) Notify(diff model.DiffCache, wg *sync.WaitGroup) error {
 	defer wg.Done()
 
 	// Create request body
@@ -73,7 +74,7 @@ func (c *WebhookNotifier) Notify(diff model.DiffCache, wg *sync.WaitGroup) {
 	payload, err := json.Marshal(reqBody)
 	if err != nil {
 		fflog.Printf(c.Logger, "error: (WebhookNotifier) impossible to read differences; %v\n", err)
-		return
+		return err
 	}
 
 	headers := http.Header{
@@ -95,11 +96,12 @@ func (c *WebhookNotifier) Notify(diff model.DiffCache, wg *sync.WaitGroup) {
 	// Log if something went wrong while calling the webhook.
 	if err != nil {
 		fflog.Printf(c.Logger, "error: while calling webhook: %v\n", err)
-		return
+		return err
 	}
 	defer response.Body.Close()
 	if response.StatusCode > 399 {
 		fflog.Printf(c.Logger, "error: while calling webhook, statusCode = %d", response.StatusCode)
-		return
+		return fmt.Errorf("webhook returned statusCode %d", response.StatusCode)
 	}
+	return nil
 }
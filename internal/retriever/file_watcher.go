@@ -0,0 +1,109 @@
+package retriever
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// FileWatcher watches a local file and calls onChange every time the file changes.
+//
+// The watcher is on the directory of the file and not on the file itself, this is the only way
+// to detect atomic writes (write in a temp file + rename) and symlink swaps used by Kubernetes
+// to update a ConfigMap.
+// The events are debounced, a burst of events triggers only one call to onChange.
+type FileWatcher struct {
+	path     string
+	realPath string
+	debounce time.Duration
+	onChange func()
+	watcher  *fsnotify.Watcher
+	timer    *time.Timer
+	mutex    sync.Mutex
+	done     chan struct{}
+}
+
+// NewFileWatcher starts to watch the file, call Close to stop watching.
+func NewFileWatcher(path string, debounce time.Duration, onChange func()) (*FileWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	path = filepath.Clean(path)
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		_ = watcher.Close()
+		return nil, err
+	}
+
+	realPath, _ := filepath.EvalSymlinks(path)
+	fw := &FileWatcher{
+		path:     path,
+		realPath: realPath,
+		debounce: debounce,
+		onChange: onChange,
+		watcher:  watcher,
+		done:     make(chan struct{}),
+	}
+	go fw.watch()
+	return fw, nil
+}
+
+// Close stops watching the file.
+func (fw *FileWatcher) Close() error {
+	err := fw.watcher.Close()
+	<-fw.done
+
+	fw.mutex.Lock()
+	defer fw.mutex.Unlock()
+	if fw.timer != nil {
+		fw.timer.Stop()
+	}
+	return err
+}
+
+func (fw *FileWatcher) watch() {
+	defer close(fw.done)
+	for {
+		select {
+		case event, ok := <-fw.watcher.Events:
+			if !ok {
+				return
+			}
+			if fw.isFileChanged(event) {
+				fw.trigger()
+			}
+		case _, ok := <-fw.watcher.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// isFileChanged checks if the event is about our file, either directly or because the target
+// of the symlink has changed.
+func (fw *FileWatcher) isFileChanged(event fsnotify.Event) bool {
+	realPath, _ := filepath.EvalSymlinks(fw.path)
+
+	fw.mutex.Lock()
+	defer fw.mutex.Unlock()
+	symlinkChanged := realPath != "" && realPath != fw.realPath
+	fw.realPath = realPath
+
+	fileEvent := filepath.Clean(event.Name) == fw.path &&
+		event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0
+	return fileEvent || symlinkChanged
+}
+
+// trigger calls onChange after the debounce duration, if another event arrives before, the timer is reset.
+func (fw *FileWatcher) trigger() {
+	fw.mutex.Lock()
+	defer fw.mutex.Unlock()
+	if fw.timer != nil {
+		fw.timer.Stop()
+	}
+	fw.timer = time.AfterFunc(fw.debounce, fw.onChange)
+}
@@ -0,0 +1,93 @@
+package retriever_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thomaspoignant/go-feature-flag/internal/retriever"
+)
+
+func TestFileWatcher_write(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watcher")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "flags.yaml")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("v1"), 0600))
+
+	var calls int32
+	watcher, err := retriever.NewFileWatcher(path, 50*time.Millisecond, func() {
+		atomic.AddInt32(&calls, 1)
+	})
+	assert.NoError(t, err)
+	defer watcher.Close()
+
+	// a burst of writes should be debounced
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, ioutil.WriteFile(path, []byte("v2"), 0600))
+	}
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// a change on another file of the directory should be ignored
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "other.yaml"), []byte("v1"), 0600))
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestFileWatcher_atomicRename(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watcher")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "flags.yaml")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("v1"), 0600))
+
+	var calls int32
+	watcher, err := retriever.NewFileWatcher(path, 50*time.Millisecond, func() {
+		atomic.AddInt32(&calls, 1)
+	})
+	assert.NoError(t, err)
+	defer watcher.Close()
+
+	tmp := filepath.Join(dir, ".flags.yaml.tmp")
+	assert.NoError(t, ioutil.WriteFile(tmp, []byte("v2"), 0600))
+	assert.NoError(t, os.Rename(tmp, path))
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestFileWatcher_symlinkSwap(t *testing.T) {
+	// reproduce the way Kubernetes updates a ConfigMap volume
+	dir, err := ioutil.TempDir("", "watcher")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "..v1"), 0700))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "..v1", "flags.yaml"), []byte("v1"), 0600))
+	assert.NoError(t, os.Symlink("..v1", filepath.Join(dir, "..data")))
+	path := filepath.Join(dir, "flags.yaml")
+	assert.NoError(t, os.Symlink(filepath.Join("..data", "flags.yaml"), path))
+
+	var calls int32
+	watcher, err := retriever.NewFileWatcher(path, 50*time.Millisecond, func() {
+		atomic.AddInt32(&calls, 1)
+	})
+	assert.NoError(t, err)
+	defer watcher.Close()
+
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "..v2"), 0700))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "..v2", "flags.yaml"), []byte("v2"), 0600))
+	assert.NoError(t, os.Symlink("..v2", filepath.Join(dir, "..data_tmp")))
+	assert.NoError(t, os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")))
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestFileWatcher_directoryNotExist(t *testing.T) {
+	_, err := retriever.NewFileWatcher("/not-a-directory/flags.yaml", 50*time.Millisecond, func() {})
+	assert.Error(t, err)
+}
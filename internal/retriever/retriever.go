@@ -1,9 +1,29 @@
 package retriever
 
-import "context"
+import (
+	"context"
+	"errors"
+)
 
 // FlagRetriever is an interface that force to have a Retrieve() function for
 // different way of getting the config file.
 type FlagRetriever interface {
 	Retrieve(ctx context.Context) ([]byte, error)
 }
+
+// ErrNotModified is returned by a FlagRetriever when the flag file has not changed since the last call.
+var ErrNotModified = errors.New("flag file not modified")
+
+// Validators identify a version of a flag file (ex: the ETag and Last-Modified headers of an HTTP response).
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
+// ConditionalRetriever is a FlagRetriever able to download the file only if it has changed since the
+// version identified by the validators. It returns ErrNotModified if the file has not changed, empty
+// validators always return the full file.
+type ConditionalRetriever interface {
+	FlagRetriever
+	RetrieveIfModified(ctx context.Context, validators Validators) ([]byte, Validators, error)
+}
@@ -0,0 +1,89 @@
+package retriever
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/thomaspoignant/go-feature-flag/internal/cache"
+)
+
+// NewDirectoryRetriever is the retriever for a directory containing multiple flag files.
+// The files are merged and returned as one JSON flag file.
+func NewDirectoryRetriever(path string, pattern string) FlagRetriever {
+	return &directoryRetriever{path: path, pattern: pattern}
+}
+
+type directoryRetriever struct {
+	path    string
+	pattern string
+}
+
+func (d *directoryRetriever) Retrieve(ctx context.Context) ([]byte, error) {
+	pattern := d.pattern
+	if pattern == "" {
+		pattern = "*"
+	}
+
+	if _, err := os.Stat(d.path); err != nil {
+		return nil, err
+	}
+
+	fileNames, err := filepath.Glob(filepath.Join(d.path, pattern))
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]cache.FlagFile, 0, len(fileNames))
+	for _, fileName := range fileNames {
+		file, ok, err := readFlagFile(fileName)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			files = append(files, file)
+		}
+	}
+
+	// the files are read like the flag files of the other retrievers, but a flag or a segment
+	// defined in more than one file of the directory is an error.
+	flags, segments, conflicts, err := cache.MergeFlagFiles(files)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 {
+		kind := "flag"
+		if conflicts[0].Segment {
+			kind = "segment"
+		}
+		return nil, fmt.Errorf("%s [%s] is defined in both %s", kind, conflicts[0].Key,
+			strings.Join(conflicts[0].Sources, " and "))
+	}
+	return cache.MarshalFlagFile(flags, segments, "json")
+}
+
+// readFlagFile reads a flag file, its format is the extension of the file.
+// ok is false if the file is a directory or has an extension we do not support.
+func readFlagFile(fileName string) (file cache.FlagFile, ok bool, err error) {
+	info, err := os.Stat(fileName)
+	if err != nil {
+		return cache.FlagFile{}, false, err
+	}
+	if info.IsDir() {
+		return cache.FlagFile{}, false, nil
+	}
+
+	ext := strings.ToLower(filepath.Ext(fileName))
+	if ext != ".yaml" && ext != ".yml" && ext != ".json" && ext != ".toml" {
+		return cache.FlagFile{}, false, nil
+	}
+
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return cache.FlagFile{}, false, err
+	}
+	return cache.FlagFile{Source: fileName, Content: content, FileFormat: strings.TrimPrefix(ext, ".")}, true, nil
+}
@@ -0,0 +1,76 @@
+package retriever_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thomaspoignant/go-feature-flag/internal/retriever"
+)
+
+func Test_directoryRetriever_Retrieve(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		pattern     string
+		want        string
+		wantErr     bool
+		errContains []string
+	}{
+		{
+			name: "All the files of the directory",
+			path: "../../testdata/flag-directory",
+			want: `{
+  "team-a-flag": {"rule": "key eq \"random-key\"", "percentage": 100, "true": true, "false": false, "default": false},
+  "team-b-flag": {
+    "rule": "segment eq \"beta-users\"", "percentage": 100, "true": "beta", "false": "not-beta", "default": "default"
+  },
+  "team-c-flag": {"percentage": 100, "true": 3, "false": 1, "default": 0},
+  "segments": {"beta-users": {"included": ["random-key"]}}
+}`,
+		},
+		{
+			name:    "Pattern",
+			path:    "../../testdata/flag-directory",
+			pattern: "*.toml",
+			want:    `{"team-c-flag": {"percentage": 100, "true": 3, "false": 1, "default": 0}}`,
+		},
+		{
+			name:        "Duplicated flag",
+			path:        "../../testdata/flag-directory-duplicate",
+			wantErr:     true,
+			errContains: []string{"team-a-flag", "team-a.yaml", "team-b.yml"},
+		},
+		{
+			name:        "Invalid flag file",
+			path:        "../../testdata/flag-directory-invalid",
+			wantErr:     true,
+			errContains: []string{"impossible to read the flags from", "invalid.yaml"},
+		},
+		{
+			name:    "Directory does not exist",
+			path:    "../../testdata/not-a-directory",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid pattern",
+			path:    "../../testdata/flag-directory",
+			pattern: "[",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := retriever.NewDirectoryRetriever(tt.path, tt.pattern)
+			got, err := r.Retrieve(context.Background())
+			assert.Equal(t, tt.wantErr, err != nil, "Retrieve() error = %v, wantErr %v", err, tt.wantErr)
+			for _, s := range tt.errContains {
+				assert.Contains(t, err.Error(), s)
+			}
+			if !tt.wantErr {
+				assert.JSONEq(t, tt.want, string(got))
+			}
+		})
+	}
+}
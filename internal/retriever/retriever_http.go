@@ -15,11 +15,11 @@ import (
 func NewHTTPRetriever(httpClient internal.HTTPClient, url string, method string,
 	body string, header http.Header) FlagRetriever {
 	return &httpRetriever{
-		httpClient,
-		url,
-		method,
-		body,
-		header,
+		httpClient: httpClient,
+		url:        url,
+		method:     method,
+		body:       body,
+		header:     header,
 	}
 }
 
@@ -32,8 +32,15 @@ type httpRetriever struct {
 }
 
 func (h *httpRetriever) Retrieve(ctx context.Context) ([]byte, error) {
+	body, _, err := h.RetrieveIfModified(ctx, Validators{})
+	return body, err
+}
+
+// RetrieveIfModified sends the validators in the request, the server answers 304 Not Modified
+// if the file has not changed. It returns the validators of the file downloaded.
+func (h *httpRetriever) RetrieveIfModified(ctx context.Context, validators Validators) ([]byte, Validators, error) {
 	if h.url == "" {
-		return nil, errors.New("URL is a mandatory parameter when using HTTPRetriever")
+		return nil, Validators{}, errors.New("URL is a mandatory parameter when using HTTPRetriever")
 	}
 
 	method := h.method
@@ -47,30 +54,45 @@ func (h *httpRetriever) Retrieve(ctx context.Context) ([]byte, error) {
 
 	req, err := http.NewRequestWithContext(ctx, method, h.url, strings.NewReader(h.body))
 	if err != nil {
-		return nil, err
+		return nil, Validators{}, err
 	}
 
 	// Add header if some are passed
 	if len(h.header) > 0 {
-		req.Header = h.header
+		req.Header = h.header.Clone()
+	}
+
+	if validators.ETag != "" {
+		req.Header.Set("If-None-Match", validators.ETag)
+	}
+	if validators.LastModified != "" {
+		req.Header.Set("If-Modified-Since", validators.LastModified)
 	}
 
 	// API call
 	resp, err := h.httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, Validators{}, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, Validators{}, ErrNotModified
+	}
+
 	// Error if http code is more that 399
 	if resp.StatusCode > 399 {
-		return nil, fmt.Errorf("request to %s failed with code %d", h.url, resp.StatusCode)
+		return nil, Validators{}, fmt.Errorf("request to %s failed with code %d", h.url, resp.StatusCode)
 	}
 
 	// read content of the URL.
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, Validators{}, err
 	}
-	return body, nil
+
+	return body, Validators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}, nil
 }
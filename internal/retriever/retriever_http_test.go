@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -171,3 +172,59 @@ func Test_httpRetriever_Retrieve(t *testing.T) {
 		})
 	}
 }
+
+func Test_httpRetriever_RetrieveNotModified(t *testing.T) {
+	tests := []struct {
+		name           string
+		responseHeader map[string]string
+		requestHeader  string
+	}{
+		{
+			name:           "ETag",
+			responseHeader: map[string]string{"ETag": `"v1"`},
+			requestHeader:  "If-None-Match",
+		},
+		{
+			name:           "Last-Modified",
+			responseHeader: map[string]string{"Last-Modified": "Wed, 21 Oct 2015 07:28:00 GMT"},
+			requestHeader:  "If-Modified-Since",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				for key, value := range tt.responseHeader {
+					if r.Header.Get(tt.requestHeader) == value {
+						w.WriteHeader(http.StatusNotModified)
+						return
+					}
+					w.Header().Set(key, value)
+				}
+				_, _ = w.Write([]byte("test-flag:\n  true: true\n"))
+			}))
+			defer srv.Close()
+
+			header := http.Header{"X-Test": []string{"test"}}
+			flagRetriever := retriever.NewHTTPRetriever(http.DefaultClient, srv.URL, http.MethodGet, "", header)
+			h := flagRetriever.(retriever.ConditionalRetriever)
+
+			got, validators, err := h.RetrieveIfModified(context.Background(), retriever.Validators{})
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("test-flag:\n  true: true\n"), got)
+			assert.NotEqual(t, retriever.Validators{}, validators)
+
+			got, _, err = h.RetrieveIfModified(context.Background(), validators)
+			assert.True(t, errors.Is(err, retriever.ErrNotModified), "second call should not download the file again")
+			assert.Nil(t, got)
+			assert.Equal(t, 2, calls)
+			assert.Len(t, header, 1, "the header of the configuration should not be modified")
+
+			got, err = h.Retrieve(context.Background())
+			assert.NoError(t, err, "Retrieve should not send the validators of the previous calls")
+			assert.Equal(t, []byte("test-flag:\n  true: true\n"), got)
+			assert.Equal(t, 3, calls)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package ffclient_test
+
+import (
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+	"github.com/thomaspoignant/go-feature-flag/ffuser"
+)
+
+// metricsMock keeps the metrics received from go-feature-flag.
+type metricsMock struct {
+	mutex            sync.Mutex
+	evaluations      []string
+	retrievals       int
+	retrievalErrors  int
+	cacheFlags       int
+	notifierFailures []string
+}
+
+func (m *metricsMock) RecordEvaluation(flagKey string, variation string, reason string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.evaluations = append(m.evaluations, flagKey+"/"+variation+"/"+reason)
+}
+
+func (m *metricsMock) RecordRetrieval(_ time.Duration, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.retrievals++
+	if err != nil {
+		m.retrievalErrors++
+	}
+}
+
+func (m *metricsMock) RecordCacheRefresh(flagCount int, _ time.Time) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.cacheFlags = flagCount
+}
+
+func (m *metricsMock) RecordExport(_ int, _ time.Duration, _ error) {}
+
+func (m *metricsMock) RecordDroppedEvents(_ int) {}
+
+func (m *metricsMock) RecordNotifierFailure(notifier string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.notifierFailures = append(m.notifierFailures, notifier)
+}
+
+func TestMetrics(t *testing.T) {
+	metrics := &metricsMock{}
+	goff, err := ffclient.New(ffclient.Config{
+		PollingInterval: 10 * time.Second,
+		Retriever:       &ffclient.FileRetriever{Path: "testdata/flag-config.yaml"},
+		Metrics:         metrics,
+	})
+	assert.NoError(t, err)
+	defer goff.Close()
+
+	_, _ = goff.BoolVariation("test-flag", ffuser.NewUser("random-key"), false)
+	_, _ = goff.BoolVariation("unknown-flag", ffuser.NewUser("random-key"), false)
+
+	assert.Equal(t, 1, metrics.retrievals)
+	assert.Equal(t, 0, metrics.retrievalErrors)
+	assert.Equal(t, 2, metrics.cacheFlags)
+	assert.Equal(t, []string{
+		"test-flag/True/TARGETING_MATCH",
+		ffclient.UnknownFlagMetricsKey + "/SdkDefault/FLAG_NOT_FOUND",
+	}, metrics.evaluations)
+}
+
+func TestMetrics_notifierFailure(t *testing.T) {
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer webhook.Close()
+
+	metrics := &metricsMock{}
+	goff, err := ffclient.New(ffclient.Config{
+		PollingInterval: 10 * time.Second,
+		Logger:          log.New(ioutil.Discard, "", 0),
+		Retriever:       &ffclient.FileRetriever{Path: "testdata/flag-config.yaml"},
+		Notifiers:       []ffclient.NotifierConfig{&ffclient.WebhookConfig{EndpointURL: webhook.URL}},
+		Metrics:         metrics,
+	})
+	assert.NoError(t, err)
+
+	// the first load of the flags is notified, Close waits for the notifiers.
+	goff.Close()
+
+	assert.Equal(t, []string{"WebhookNotifier"}, metrics.notifierFailures)
+}
+
+func TestMetrics_invalidFlagFile(t *testing.T) {
+	file, err := ioutil.TempFile("", "invalid-flags-*.yaml")
+	assert.NoError(t, err)
+	defer os.Remove(file.Name())
+	_, _ = file.WriteString("test-flag:\n  percentage: 120\n  true: true\n  false: false\n  default: false\n")
+	_ = file.Close()
+
+	metrics := &metricsMock{}
+	goff, err := ffclient.New(ffclient.Config{
+		PollingInterval:         10 * time.Second,
+		Logger:                  log.New(ioutil.Discard, "", 0),
+		Retriever:               &ffclient.FileRetriever{Path: file.Name()},
+		Metrics:                 metrics,
+		StartWithRetrieverError: true,
+	})
+	assert.NoError(t, err)
+	defer goff.Close()
+
+	// the file has been retrieved but the cache has not been updated.
+	assert.Equal(t, 1, metrics.retrievals)
+	assert.Equal(t, 1, metrics.retrievalErrors)
+}
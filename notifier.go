@@ -1,12 +1,26 @@
 package ffclient
 
 import (
+	"github.com/thomaspoignant/go-feature-flag/internal/model"
 	"github.com/thomaspoignant/go-feature-flag/internal/notifier"
 )
 
+// Notifier is called every time the flags have changed, implement it and return it from a NotifierConfig
+// to be informed of the changes.
+// Notify is called in a goroutine, it should call waitGroup.Done() when it has finished and return an error
+// if the notification has not been sent.
+// A notifier receives the changes in the order of the updates, the next call waits for the previous one.
+type Notifier = notifier.Notifier
+
+// DiffCache contains the changes of the flags (deleted, added and updated) sent to the notifiers.
+type DiffCache = model.DiffCache
+
+// DiffUpdated is a flag updated, with its configuration before and after the change.
+type DiffUpdated = model.DiffUpdated
+
 // getNotifiers is creating Notifier from the config
-func getNotifiers(config Config) ([]notifier.Notifier, error) {
-	notifiers := make([]notifier.Notifier, 0)
+func getNotifiers(config Config) ([]Notifier, error) {
+	notifiers := make([]Notifier, 0)
 	if config.Logger != nil {
 		notifiers = append(notifiers, &notifier.LogNotifier{Logger: config.Logger})
 	}
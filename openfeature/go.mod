@@ -0,0 +1,29 @@
+module github.com/thomaspoignant/go-feature-flag/openfeature
+
+go 1.18
+
+require (
+	github.com/open-feature/go-sdk v1.8.0
+	github.com/stretchr/testify v1.8.4
+	github.com/thomaspoignant/go-feature-flag v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/antlr/antlr4 v0.0.0-20201206235148-c87e55b61113 // indirect
+	github.com/aws/aws-sdk-go v1.38.30 // indirect
+	github.com/blang/semver v3.5.1+incompatible // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/fsnotify/fsnotify v1.4.9 // indirect
+	github.com/go-logr/logr v1.2.4 // indirect
+	github.com/google/go-cmp v0.5.8 // indirect
+	github.com/jmespath/go-jmespath v0.4.0 // indirect
+	github.com/nikunjy/rules v0.0.0-20200120082459-0b7c4dc9dc86 // indirect
+	github.com/pelletier/go-toml v1.9.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/exp v0.0.0-20230811145659-89c5cff77bcb // indirect
+	golang.org/x/sys v0.1.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// the module is released with go-feature-flag, it always uses the version of the same commit.
+replace github.com/thomaspoignant/go-feature-flag => ../
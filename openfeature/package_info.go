@@ -0,0 +1,12 @@
+// Package openfeature is an OpenFeature provider for go-feature-flag.
+//
+// It is a separate module because the OpenFeature Go SDK needs a more recent version of Go than go-feature-flag.
+//
+//	provider := openfeature.NewProvider(ffclient.Config{
+//	  PollingInterval: 10 * time.Second,
+//	  Retriever: &ffclient.FileRetriever{Path: "flag-config.yaml"},
+//	})
+//	of.SetProvider(provider)
+//	client := of.NewClient("my-app")
+//	hasFlag, _ := client.BooleanValue(ctx, "test-flag", false, of.NewEvaluationContext("user-123", nil))
+package openfeature
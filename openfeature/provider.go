@@ -0,0 +1,379 @@
+package openfeature
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	of "github.com/open-feature/go-sdk/pkg/openfeature"
+
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+	"github.com/thomaspoignant/go-feature-flag/ffuser"
+)
+
+// providerName is the name of the provider in the OpenFeature metadata and events.
+const providerName = "go-feature-flag"
+
+// anonymousAttribute is the attribute of the evaluation context used to mark the user as anonymous.
+const anonymousAttribute = "anonymous"
+
+// eventBufferSize is the number of events waiting for the OpenFeature SDK, the next events are dropped.
+const eventBufferSize = 16
+
+// Provider is an OpenFeature provider evaluating the flags with go-feature-flag.
+type Provider struct {
+	config ffclient.Config
+	events chan of.Event
+
+	mutex  sync.RWMutex
+	goff   *ffclient.GoFeatureFlag
+	status of.State
+	// initializing is true while Init is starting go-feature-flag.
+	initializing bool
+	// loaded is true once we have received the notification of the first load of the flags.
+	loaded bool
+}
+
+// NewProvider creates a provider with the configuration of go-feature-flag,
+// the flags are retrieved when the OpenFeature SDK initializes the provider.
+func NewProvider(config ffclient.Config) *Provider {
+	return &Provider{
+		config: config,
+		events: make(chan of.Event, eventBufferSize),
+		status: of.NotReadyState,
+	}
+}
+
+// Metadata returns the name of the provider.
+func (p *Provider) Metadata() of.Metadata {
+	return of.Metadata{Name: providerName}
+}
+
+// Hooks returns the hooks of the provider, there is none.
+func (p *Provider) Hooks() []of.Hook {
+	return nil
+}
+
+// Init starts go-feature-flag, it is called by the OpenFeature SDK when the provider is set.
+// If the flags are not available (Config.StartWithRetrieverError), an error is returned and
+// a PROVIDER_READY event is sent when the flags are retrieved.
+func (p *Provider) Init(_ of.EvaluationContext) error {
+	config := p.config
+	config.Notifiers = append(append([]ffclient.NotifierConfig{}, p.config.Notifiers...), providerNotifier{p})
+	recorder := p.config.Metrics
+	if recorder == nil {
+		recorder = ffclient.NoopMetricsRecorder{}
+	}
+	config.Metrics = refreshRecorder{MetricsRecorder: recorder, provider: p}
+
+	p.mutex.Lock()
+	p.initializing = true
+	p.mutex.Unlock()
+
+	goff, err := ffclient.New(config)
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.initializing = false
+	if err != nil {
+		p.status = of.ErrorState
+		return err
+	}
+	p.goff = goff
+	if goff.GetCacheStatus().LastUpdate.IsZero() {
+		p.status = of.ErrorState
+		return errors.New("impossible to retrieve the flags, waiting for the next retrieval")
+	}
+	p.status = of.ReadyState
+	return nil
+}
+
+// Shutdown stops go-feature-flag.
+func (p *Provider) Shutdown() {
+	p.mutex.Lock()
+	goff := p.goff
+	p.goff = nil
+	p.status = of.NotReadyState
+	p.mutex.Unlock()
+
+	// Close waits for the notifications in progress, we cannot keep the lock.
+	if goff != nil {
+		goff.Close()
+	}
+}
+
+// Status returns the state of the provider.
+func (p *Provider) Status() of.State {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.status
+}
+
+// EventChannel returns the events of the provider, it is read by the OpenFeature SDK.
+func (p *Provider) EventChannel() <-chan of.Event {
+	return p.events
+}
+
+// BooleanEvaluation evaluates a boolean flag.
+func (p *Provider) BooleanEvaluation(
+	ctx context.Context, flag string, defaultValue bool, evalCtx of.FlattenedContext) of.BoolResolutionDetail {
+	goff, user, resErr := p.prepare(evalCtx)
+	if resErr != nil {
+		return of.BoolResolutionDetail{Value: defaultValue, ProviderResolutionDetail: errorDetail(*resErr)}
+	}
+	res, err := goff.BoolVariationDetailsCtx(ctx, flag, user, defaultValue)
+	return of.BoolResolutionDetail{
+		Value:                    res.Value,
+		ProviderResolutionDetail: resolutionDetail(res.EvaluationDetails, err),
+	}
+}
+
+// StringEvaluation evaluates a string flag.
+func (p *Provider) StringEvaluation(
+	ctx context.Context, flag string, defaultValue string, evalCtx of.FlattenedContext) of.StringResolutionDetail {
+	goff, user, resErr := p.prepare(evalCtx)
+	if resErr != nil {
+		return of.StringResolutionDetail{Value: defaultValue, ProviderResolutionDetail: errorDetail(*resErr)}
+	}
+	res, err := goff.StringVariationDetailsCtx(ctx, flag, user, defaultValue)
+	return of.StringResolutionDetail{
+		Value:                    res.Value,
+		ProviderResolutionDetail: resolutionDetail(res.EvaluationDetails, err),
+	}
+}
+
+// FloatEvaluation evaluates a float64 flag.
+func (p *Provider) FloatEvaluation(
+	ctx context.Context, flag string, defaultValue float64, evalCtx of.FlattenedContext) of.FloatResolutionDetail {
+	goff, user, resErr := p.prepare(evalCtx)
+	if resErr != nil {
+		return of.FloatResolutionDetail{Value: defaultValue, ProviderResolutionDetail: errorDetail(*resErr)}
+	}
+	res, err := goff.Float64VariationDetailsCtx(ctx, flag, user, defaultValue)
+	return of.FloatResolutionDetail{
+		Value:                    res.Value,
+		ProviderResolutionDetail: resolutionDetail(res.EvaluationDetails, err),
+	}
+}
+
+// IntEvaluation evaluates an integer flag.
+func (p *Provider) IntEvaluation(
+	ctx context.Context, flag string, defaultValue int64, evalCtx of.FlattenedContext) of.IntResolutionDetail {
+	goff, user, resErr := p.prepare(evalCtx)
+	if resErr != nil {
+		return of.IntResolutionDetail{Value: defaultValue, ProviderResolutionDetail: errorDetail(*resErr)}
+	}
+	res, err := goff.IntVariationDetailsCtx(ctx, flag, user, int(defaultValue))
+	return of.IntResolutionDetail{
+		Value:                    int64(res.Value),
+		ProviderResolutionDetail: resolutionDetail(res.EvaluationDetails, err),
+	}
+}
+
+// ObjectEvaluation evaluates a flag of any type, the value is not converted.
+func (p *Provider) ObjectEvaluation(ctx context.Context, flag string, defaultValue interface{},
+	evalCtx of.FlattenedContext) of.InterfaceResolutionDetail {
+	goff, user, resErr := p.prepare(evalCtx)
+	if resErr != nil {
+		return of.InterfaceResolutionDetail{Value: defaultValue, ProviderResolutionDetail: errorDetail(*resErr)}
+	}
+	res, err := goff.RawVariationDetailsCtx(ctx, flag, user, defaultValue)
+	return of.InterfaceResolutionDetail{
+		Value:                    res.Value,
+		ProviderResolutionDetail: resolutionDetail(res.EvaluationDetails, err),
+	}
+}
+
+// prepare returns the go-feature-flag instance and the user of the evaluation context.
+func (p *Provider) prepare(evalCtx of.FlattenedContext) (*ffclient.GoFeatureFlag, ffuser.User, *of.ResolutionError) {
+	p.mutex.RLock()
+	goff := p.goff
+	ready := p.status == of.ReadyState
+	p.mutex.RUnlock()
+	if goff == nil || !ready {
+		resErr := of.NewProviderNotReadyResolutionError("the flags are not available")
+		return nil, ffuser.User{}, &resErr
+	}
+
+	user, err := userFromContext(evalCtx)
+	if err != nil {
+		return nil, ffuser.User{}, err
+	}
+	return goff, user, nil
+}
+
+// userFromContext converts the evaluation context in a user:
+// targetingKey is the key of the user, anonymous is the anonymous flag and the other attributes are custom.
+func userFromContext(evalCtx of.FlattenedContext) (ffuser.User, *of.ResolutionError) {
+	key, ok := evalCtx[of.TargetingKey].(string)
+	if !ok || key == "" {
+		resErr := of.NewTargetingKeyMissingResolutionError("the evaluation context needs a targetingKey")
+		return ffuser.User{}, &resErr
+	}
+
+	builder := ffuser.NewUserBuilder(key)
+	for name, value := range evalCtx {
+		switch name {
+		case of.TargetingKey:
+			// already used as the key of the user
+		case anonymousAttribute:
+			anonymous, ok := value.(bool)
+			if !ok {
+				resErr := of.NewInvalidContextResolutionError(
+					fmt.Sprintf("attribute %s should be a boolean, got %T", anonymousAttribute, value))
+				return ffuser.User{}, &resErr
+			}
+			builder.Anonymous(anonymous)
+		default:
+			builder.AddCustom(name, value)
+		}
+	}
+	return builder.Build(), nil
+}
+
+// resolutionDetail converts the details of a go-feature-flag evaluation.
+func resolutionDetail(details ffclient.EvaluationDetails, err error) of.ProviderResolutionDetail {
+	res := of.ProviderResolutionDetail{
+		Reason:  reason(details.Reason),
+		Variant: details.Variation,
+	}
+	if details.RuleName != "" {
+		res.FlagMetadata = of.FlagMetadata{"ruleName": details.RuleName}
+	}
+	if err == nil {
+		return res
+	}
+	switch details.ErrorCode {
+	case ffclient.ErrorCodeFlagNotFound:
+		res.ResolutionError = of.NewFlagNotFoundResolutionError(err.Error())
+	case ffclient.ErrorCodeTypeMismatch:
+		res.ResolutionError = of.NewTypeMismatchResolutionError(err.Error())
+	case ffclient.ErrorCodeGeneral:
+		res.ResolutionError = of.NewGeneralResolutionError(err.Error())
+	default:
+		// a disabled flag serves the default value, this is not an error for OpenFeature.
+	}
+	return res
+}
+
+// errorDetail returns the details of an evaluation that has not been done.
+func errorDetail(resErr of.ResolutionError) of.ProviderResolutionDetail {
+	return of.ProviderResolutionDetail{ResolutionError: resErr, Reason: of.ErrorReason}
+}
+
+// reason converts the reason of go-feature-flag, the reasons without equivalent in OpenFeature are kept as is.
+func reason(r ffclient.ResolutionReason) of.Reason {
+	switch r {
+	case ffclient.ReasonFlagNotFound, ffclient.ReasonTypeMismatch, ffclient.ReasonError:
+		return of.ErrorReason
+	default:
+		return of.Reason(r)
+	}
+}
+
+// notify is called every time the flags have changed, the first load of the flags is already
+// announced by the OpenFeature SDK when Init succeeds.
+func (p *Provider) notify(diff ffclient.DiffCache) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	var event of.Event
+	switch {
+	case p.initializing:
+		// the first load of the flags, Init will set the status.
+		p.loaded = true
+		return
+	case p.goff == nil:
+		// the provider has been shut down.
+		return
+	case p.status != of.ReadyState:
+		p.status = of.ReadyState
+		event = of.Event{ProviderName: providerName, EventType: of.ProviderReady}
+	case !p.loaded:
+		p.loaded = true
+		return
+	default:
+		event = of.Event{
+			ProviderName: providerName,
+			EventType:    of.ProviderConfigChange,
+			ProviderEventDetails: of.ProviderEventDetails{
+				Message:     "the flags have changed",
+				FlagChanges: changedFlags(diff),
+			},
+		}
+	}
+	p.loaded = true
+
+	select {
+	case p.events <- event:
+	default:
+		// nobody is reading the events, we don't block the notifications.
+	}
+}
+
+// refreshed is called after every successful retrieval of the flags, the provider becomes ready
+// even if the flags have not changed since Init failed.
+func (p *Provider) refreshed(flagCount int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.initializing || p.goff == nil || p.status == of.ReadyState {
+		return
+	}
+	p.status = of.ReadyState
+	// the cache was empty when Init failed, the first load is notified only if it contains flags.
+	p.loaded = p.loaded || flagCount == 0
+
+	select {
+	case p.events <- of.Event{ProviderName: providerName, EventType: of.ProviderReady}:
+	default:
+		// nobody is reading the events, we don't block the retrieval of the flags.
+	}
+}
+
+// changedFlags returns the sorted names of the flags added, deleted or updated.
+func changedFlags(diff ffclient.DiffCache) []string {
+	res := make([]string, 0, len(diff.Added)+len(diff.Deleted)+len(diff.Updated))
+	for key := range diff.Added {
+		res = append(res, key)
+	}
+	for key := range diff.Deleted {
+		res = append(res, key)
+	}
+	for key := range diff.Updated {
+		res = append(res, key)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// refreshRecorder forwards the metrics to the recorder of the configuration and informs the provider
+// of every successful retrieval of the flags.
+type refreshRecorder struct {
+	ffclient.MetricsRecorder
+	provider *Provider
+}
+
+// RecordCacheRefresh records the refresh and informs the provider.
+func (r refreshRecorder) RecordCacheRefresh(flagCount int, refreshedAt time.Time) {
+	r.MetricsRecorder.RecordCacheRefresh(flagCount, refreshedAt)
+	r.provider.refreshed(flagCount)
+}
+
+// providerNotifier receives the notifications of go-feature-flag for the provider.
+type providerNotifier struct {
+	provider *Provider
+}
+
+// GetNotifier allows to use the providerNotifier in the Notifiers of the configuration.
+func (n providerNotifier) GetNotifier(_ ffclient.Config) (ffclient.Notifier, error) {
+	return n, nil
+}
+
+// Notify forwards the changes to the provider.
+func (n providerNotifier) Notify(diff ffclient.DiffCache, wg *sync.WaitGroup) error {
+	defer wg.Done()
+	n.provider.notify(diff)
+	return nil
+}
@@ -0,0 +1,230 @@
+package openfeature_test
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	of "github.com/open-feature/go-sdk/pkg/openfeature"
+	"github.com/stretchr/testify/assert"
+
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+	"github.com/thomaspoignant/go-feature-flag/openfeature"
+)
+
+const flagFile = `test-flag:
+  rule: key eq "random-key"
+  percentage: 100
+  true: true
+  false: false
+  default: false
+
+beta-flag:
+  variations:
+    A: "checkout-v1"
+    B: "checkout-v2"
+  targeting:
+    - name: beta-testers
+      query: beta eq true and anonymous eq false
+      variation: B
+  default: "checkout-v1"
+
+number-flag:
+  true: 12
+  false: 12
+  default: 12
+
+float-flag:
+  true: 12.5
+  false: 12.5
+  default: 12.5
+
+object-flag:
+  true: {"color": "blue"}
+  false: {"color": "blue"}
+  default: {"color": "blue"}
+
+disabled-flag:
+  true: true
+  false: false
+  default: false
+  disable: true
+`
+
+func newProvider(path string, startWithRetrieverError bool) *openfeature.Provider {
+	return openfeature.NewProvider(ffclient.Config{
+		PollingInterval:         time.Second,
+		Logger:                  log.New(ioutil.Discard, "", 0),
+		Retriever:               &ffclient.FileRetriever{Path: path},
+		StartWithRetrieverError: startWithRetrieverError,
+	})
+}
+
+func waitEvent(t *testing.T, provider *openfeature.Provider) of.Event {
+	select {
+	case event := <-provider.EventChannel():
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event received")
+		return of.Event{}
+	}
+}
+
+func TestProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "openfeature")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "flags.yaml")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(flagFile), 0600))
+
+	provider := newProvider(path, false)
+	assert.Equal(t, of.NotReadyState, provider.Status())
+	assert.NoError(t, provider.Init(of.EvaluationContext{}))
+	defer provider.Shutdown()
+	assert.Equal(t, of.ReadyState, provider.Status())
+	ctx := context.Background()
+
+	t.Run("Bool", func(t *testing.T) {
+		got := provider.BooleanEvaluation(ctx, "test-flag", false, of.FlattenedContext{of.TargetingKey: "random-key"})
+		assert.True(t, got.Value)
+		assert.Equal(t, "True", got.Variant)
+		assert.Equal(t, of.TargetingMatchReason, got.Reason)
+		assert.NoError(t, got.Error())
+	})
+
+	t.Run("String with attributes and rule name", func(t *testing.T) {
+		evalCtx := of.FlattenedContext{of.TargetingKey: "user-1", "beta": true, "anonymous": false}
+		got := provider.StringEvaluation(ctx, "beta-flag", "default", evalCtx)
+		assert.Equal(t, "checkout-v2", got.Value)
+		assert.Equal(t, "B", got.Variant)
+		assert.Equal(t, of.FlagMetadata{"ruleName": "beta-testers"}, got.FlagMetadata)
+
+		evalCtx["anonymous"] = true
+		got = provider.StringEvaluation(ctx, "beta-flag", "default", evalCtx)
+		assert.Equal(t, "checkout-v1", got.Value)
+	})
+
+	t.Run("Int and float", func(t *testing.T) {
+		gotInt := provider.IntEvaluation(ctx, "number-flag", 0, of.FlattenedContext{of.TargetingKey: "random-key"})
+		assert.Equal(t, int64(12), gotInt.Value)
+		gotFloat := provider.FloatEvaluation(ctx, "float-flag", 0, of.FlattenedContext{of.TargetingKey: "random-key"})
+		assert.Equal(t, 12.5, gotFloat.Value)
+	})
+
+	t.Run("Object", func(t *testing.T) {
+		got := provider.ObjectEvaluation(ctx, "object-flag", nil, of.FlattenedContext{of.TargetingKey: "random-key"})
+		assert.Equal(t, map[string]interface{}{"color": "blue"}, got.Value)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		got := provider.BooleanEvaluation(ctx, "unknown-flag", true, of.FlattenedContext{of.TargetingKey: "random-key"})
+		assert.True(t, got.Value)
+		assert.Equal(t, of.FlagNotFoundCode, got.ResolutionDetail().ErrorCode)
+		assert.Equal(t, of.ErrorReason, got.Reason)
+
+		gotStr := provider.StringEvaluation(ctx, "test-flag", "default", of.FlattenedContext{of.TargetingKey: "random-key"})
+		assert.Equal(t, "default", gotStr.Value)
+		assert.Equal(t, of.TypeMismatchCode, gotStr.ResolutionDetail().ErrorCode)
+
+		got = provider.BooleanEvaluation(ctx, "test-flag", true, of.FlattenedContext{"email": "john.doe@example.com"})
+		assert.True(t, got.Value)
+		assert.Equal(t, of.TargetingKeyMissingCode, got.ResolutionDetail().ErrorCode)
+
+		got = provider.BooleanEvaluation(ctx, "test-flag", true,
+			of.FlattenedContext{of.TargetingKey: "random-key", "anonymous": "yes"})
+		assert.Equal(t, of.InvalidContextCode, got.ResolutionDetail().ErrorCode)
+	})
+
+	t.Run("Disabled flag", func(t *testing.T) {
+		got := provider.BooleanEvaluation(ctx, "disabled-flag", true, of.FlattenedContext{of.TargetingKey: "random-key"})
+		assert.True(t, got.Value)
+		assert.Equal(t, of.DisabledReason, got.Reason)
+		assert.NoError(t, got.Error())
+	})
+
+	t.Run("Configuration changed", func(t *testing.T) {
+		updated := flagFile + "\nnew-flag:\n  true: true\n  false: false\n  default: false\n"
+		assert.NoError(t, ioutil.WriteFile(path, []byte(updated), 0600))
+		event := waitEvent(t, provider)
+		assert.Equal(t, of.ProviderConfigChange, event.EventType)
+		assert.Equal(t, "go-feature-flag", event.ProviderName)
+		assert.Equal(t, []string{"new-flag"}, event.FlagChanges)
+	})
+}
+
+func TestProvider_startWithRetrieverError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "openfeature")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "flags.yaml")
+
+	provider := newProvider(path, true)
+	assert.Error(t, provider.Init(of.EvaluationContext{}))
+	defer provider.Shutdown()
+	assert.Equal(t, of.ErrorState, provider.Status())
+
+	got := provider.BooleanEvaluation(context.Background(), "test-flag", false,
+		of.FlattenedContext{of.TargetingKey: "random-key"})
+	assert.False(t, got.Value)
+	assert.Equal(t, of.ProviderNotReadyCode, got.ResolutionDetail().ErrorCode)
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte(flagFile), 0600))
+	event := waitEvent(t, provider)
+	assert.Equal(t, of.ProviderReady, event.EventType)
+	assert.Equal(t, of.ReadyState, provider.Status())
+
+	got = provider.BooleanEvaluation(context.Background(), "test-flag", false,
+		of.FlattenedContext{of.TargetingKey: "random-key"})
+	assert.True(t, got.Value)
+}
+
+func TestProvider_openFeatureClient(t *testing.T) {
+	provider := newProvider("../testdata/flag-config.yaml", false)
+	ready := make(chan struct{})
+	onReady := func(details of.EventDetails) { close(ready) }
+	of.AddHandler(of.ProviderReady, &onReady)
+	defer of.RemoveHandler(of.ProviderReady, &onReady)
+	assert.NoError(t, of.SetProvider(provider))
+	defer of.Shutdown()
+
+	select {
+	case <-ready:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the provider is not ready")
+	}
+
+	client := of.NewClient("test")
+	got, err := client.BooleanValueDetails(context.Background(), "test-flag", false,
+		of.NewEvaluationContext("random-key", map[string]interface{}{"email": "john.doe@example.com"}))
+	assert.NoError(t, err)
+	assert.True(t, got.Value)
+	assert.Equal(t, "True", got.Variant)
+	assert.Equal(t, of.TargetingMatchReason, got.Reason)
+}
+
+func TestProvider_startWithRetrieverErrorWithoutFlags(t *testing.T) {
+	dir, err := ioutil.TempDir("", "openfeature")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "flags.yaml")
+
+	provider := newProvider(path, true)
+	assert.Error(t, provider.Init(of.EvaluationContext{}))
+	defer provider.Shutdown()
+	assert.Equal(t, of.ErrorState, provider.Status())
+
+	// the file has no flag, there is no change to notify but the flags are available.
+	assert.NoError(t, ioutil.WriteFile(path, []byte(""), 0600))
+	event := waitEvent(t, provider)
+	assert.Equal(t, of.ProviderReady, event.EventType)
+	assert.Equal(t, of.ReadyState, provider.Status())
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte(flagFile), 0600))
+	event = waitEvent(t, provider)
+	assert.Equal(t, of.ProviderConfigChange, event.EventType)
+	assert.Contains(t, event.FlagChanges, "test-flag")
+}
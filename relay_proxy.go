@@ -0,0 +1,213 @@
+package ffclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thomaspoignant/go-feature-flag/ffuser"
+	"github.com/thomaspoignant/go-feature-flag/internal"
+)
+
+// RelayProxy is the configuration to evaluate the flags with a remote relay proxy instead of
+// retrieving the flags locally, see cmd/relayproxy.
+type RelayProxy struct {
+	// URL is the address of the relay proxy (ex: http://localhost:1031).
+	URL string
+
+	// Header (optional) is added to every request sent to the relay proxy.
+	Header http.Header
+
+	// Timeout (optional) is the maximum time to wait for an evaluation,
+	// the SDK default value is served if the relay proxy does not answer in time.
+	// Default: 1 second
+	Timeout time.Duration
+
+	// CacheTTL (optional) is the time we keep the result of an evaluation for a user and a flag,
+	// a negative value disables the cache.
+	// Default: 30 seconds
+	CacheTTL time.Duration
+
+	// MaxCacheSize (optional) is the maximum number of evaluations in the cache.
+	// Default: 10000
+	MaxCacheSize int
+}
+
+// remoteEvaluator evaluates the flags by calling the relay proxy.
+type remoteEvaluator struct {
+	url          string
+	header       http.Header
+	timeout      time.Duration
+	cacheTTL     time.Duration
+	maxCacheSize int
+	httpClient   internal.HTTPClient
+
+	cache      map[string]remoteCacheEntry
+	cacheMutex sync.Mutex
+}
+
+// remoteCacheEntry is a successful evaluation kept in the cache of the remoteEvaluator.
+type remoteCacheEntry struct {
+	value     interface{}
+	details   EvaluationDetails
+	expiresAt time.Time
+}
+
+// remoteEvalRequest is the body sent to the relay proxy to evaluate a flag.
+type remoteEvalRequest struct {
+	User         ffuser.User `json:"user"`
+	DefaultValue interface{} `json:"defaultValue"`
+}
+
+// remoteEvalResponse is the evaluation of a flag returned by the relay proxy.
+type remoteEvalResponse struct {
+	Value interface{} `json:"value"`
+	EvaluationDetails
+	Failed bool `json:"failed"`
+}
+
+// newRemoteEvaluator checks the configuration of the relay proxy and set the default values.
+func newRemoteEvaluator(config RelayProxy) (*remoteEvaluator, error) {
+	if _, err := url.ParseRequestURI(config.URL); err != nil {
+		return nil, fmt.Errorf("invalid relay proxy URL %q: %v", config.URL, err)
+	}
+	r := &remoteEvaluator{
+		url:          strings.TrimSuffix(config.URL, "/"),
+		header:       config.Header,
+		timeout:      config.Timeout,
+		cacheTTL:     config.CacheTTL,
+		maxCacheSize: config.MaxCacheSize,
+		cache:        make(map[string]remoteCacheEntry),
+	}
+	if r.timeout <= 0 {
+		r.timeout = time.Second
+	}
+	r.httpClient = internal.HTTPClientWithTimeout(r.timeout)
+	if r.cacheTTL == 0 {
+		r.cacheTTL = 30 * time.Second
+	}
+	if r.maxCacheSize <= 0 {
+		r.maxCacheSize = 10000
+	}
+	return r, nil
+}
+
+// evaluate returns the value of the flag for the user, from the cache if possible.
+// If the relay proxy is not reachable we return the sdkDefault value and the details of the error.
+func (r *remoteEvaluator) evaluate(ctx context.Context, flagKey string, user ffuser.User,
+	sdkDefault interface{}) (interface{}, EvaluationDetails, error) {
+	userJSON, err := json.Marshal(user)
+	if err != nil {
+		return sdkDefault, SDKDefaultDetails(ReasonError, ErrorCodeGeneral), err
+	}
+	cacheKey := flagKey + "\x00" + string(userJSON)
+	if entry, ok := r.getCache(cacheKey); ok {
+		return entry.value, entry.details, nil
+	}
+
+	var resp remoteEvalResponse
+	err = r.call(ctx, "/v1/feature/"+url.PathEscape(flagKey)+"/eval",
+		remoteEvalRequest{User: user, DefaultValue: sdkDefault}, &resp)
+	if err != nil {
+		return sdkDefault, SDKDefaultDetails(ReasonError, ErrorCodeGeneral),
+			fmt.Errorf("impossible to evaluate the flag %s with the relay proxy: %v", flagKey, err)
+	}
+	if resp.Failed {
+		if resp.ErrorCode == ErrorCodeTypeMismatch {
+			return sdkDefault, resp.EvaluationDetails, WrongVariationError(flagKey)
+		}
+		return sdkDefault, resp.EvaluationDetails, fmt.Errorf(errorFlagNotAvailable, flagKey)
+	}
+
+	r.setCache(cacheKey, remoteCacheEntry{value: resp.Value, details: resp.EvaluationDetails})
+	return resp.Value, resp.EvaluationDetails, nil
+}
+
+// allFlagsState returns the evaluation of all the flags for the user.
+func (r *remoteEvaluator) allFlagsState(user ffuser.User) AllFlags {
+	var res AllFlags
+	err := r.call(context.Background(), "/v1/allflags", struct {
+		User ffuser.User `json:"user"`
+	}{User: user}, &res)
+	if err != nil || res.Flags == nil {
+		return AllFlags{Flags: make(map[string]FlagState), Valid: false}
+	}
+	return res
+}
+
+// call sends a POST request to the relay proxy and reads the JSON response in res.
+func (r *remoteEvaluator) call(ctx context.Context, path string, body interface{}, res interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url+path, bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	for name, values := range r.header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("the relay proxy returned %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return json.Unmarshal(respBody, res)
+}
+
+// getCache returns the evaluation in the cache if it has not expired.
+func (r *remoteEvaluator) getCache(key string) (remoteCacheEntry, bool) {
+	if r.cacheTTL < 0 {
+		return remoteCacheEntry{}, false
+	}
+	r.cacheMutex.Lock()
+	defer r.cacheMutex.Unlock()
+	entry, ok := r.cache[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return remoteCacheEntry{}, false
+	}
+	return entry, true
+}
+
+// setCache keeps the evaluation in the cache, if the cache is full we remove the expired evaluations
+// and we clear it if it is still full.
+func (r *remoteEvaluator) setCache(key string, entry remoteCacheEntry) {
+	if r.cacheTTL < 0 {
+		return
+	}
+	r.cacheMutex.Lock()
+	defer r.cacheMutex.Unlock()
+	now := time.Now()
+	if len(r.cache) >= r.maxCacheSize {
+		for k, e := range r.cache {
+			if now.After(e.expiresAt) {
+				delete(r.cache, k)
+			}
+		}
+		if len(r.cache) >= r.maxCacheSize {
+			r.cache = make(map[string]remoteCacheEntry)
+		}
+	}
+	entry.expiresAt = now.Add(r.cacheTTL)
+	r.cache[key] = entry
+}
@@ -0,0 +1,190 @@
+package ffclient_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	ffclient "github.com/thomaspoignant/go-feature-flag"
+	"github.com/thomaspoignant/go-feature-flag/ffuser"
+)
+
+// newRelayProxyMock returns a fake relay proxy, test-flag is true for random-key and false for the other users.
+func newRelayProxyMock(calls *int32, delay time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		time.Sleep(delay)
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var body struct {
+			User         ffuser.User `json:"user"`
+			DefaultValue interface{} `json:"defaultValue"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v1/feature/test-flag/eval":
+			if body.User.GetKey() == "random-key" {
+				_, _ = w.Write([]byte(`{"value": true, "variation": "True", "reason": "TARGETING_MATCH", "failed": false}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"value": false, "variation": "Default", "reason": "DEFAULT", "failed": false}`))
+		case "/v1/feature/json-flag/eval":
+			_, _ = w.Write([]byte(`{"value": {"size": 12, "tags": ["a"]}, "variation": "True", "reason": "STATIC",
+				"failed": false}`))
+		case "/v1/allflags":
+			_, _ = w.Write([]byte(`{"flags": {"test-flag": {"value": true, "variation": "True", "reason": "STATIC",
+				"trackEvents": true}}, "valid": true}`))
+		default:
+			res, _ := json.Marshal(map[string]interface{}{
+				"value": body.DefaultValue, "variation": "SdkDefault", "reason": "FLAG_NOT_FOUND",
+				"errorCode": "FLAG_NOT_FOUND", "failed": true,
+			})
+			_, _ = w.Write(res)
+		}
+	}))
+}
+
+func TestRelayProxy(t *testing.T) {
+	var calls int32
+	srv := newRelayProxyMock(&calls, 0)
+	defer srv.Close()
+
+	goff, err := ffclient.New(ffclient.Config{
+		RelayProxy: &ffclient.RelayProxy{
+			URL:    srv.URL,
+			Header: http.Header{"Authorization": []string{"Bearer token"}},
+		},
+	})
+	assert.NoError(t, err)
+	defer goff.Close()
+	user := ffuser.NewUser("random-key")
+
+	got, err := goff.BoolVariation("test-flag", user, false)
+	assert.NoError(t, err)
+	assert.True(t, got)
+
+	got, err = goff.BoolVariation("test-flag", ffuser.NewUser("other-key"), true)
+	assert.NoError(t, err)
+	assert.False(t, got)
+
+	details, err := goff.BoolVariationDetails("test-flag", user, false)
+	assert.NoError(t, err)
+	assert.Equal(t, ffclient.BoolEvaluationDetails{
+		Value:             true,
+		EvaluationDetails: ffclient.EvaluationDetails{Variation: "True", Reason: ffclient.ReasonTargetingMatch},
+	}, details)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "the 2nd evaluation for random-key should use the cache")
+
+	jsonValue, err := goff.JSONVariation("json-flag", user, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"size": float64(12), "tags": []interface{}{"a"}}, jsonValue)
+
+	strValue, err := goff.StringVariationDetails("test-flag", user, "default")
+	assert.EqualError(t, err, "wrong variation used for flag test-flag")
+	assert.Equal(t, "default", strValue.Value)
+	assert.Equal(t, ffclient.ErrorCodeTypeMismatch, strValue.ErrorCode)
+
+	intValue, err := goff.IntVariationDetails("unknown-flag", user, 42)
+	assert.EqualError(t, err, "flag unknown-flag is not present or disabled")
+	assert.Equal(t, 42, intValue.Value)
+	assert.Equal(t, ffclient.ErrorCodeFlagNotFound, intValue.ErrorCode)
+
+	allFlags := goff.AllFlagsState(user)
+	assert.True(t, allFlags.Valid)
+	assert.Equal(t, map[string]ffclient.FlagState{
+		"test-flag": {Value: true, Variation: "True", Reason: ffclient.ReasonStatic, TrackEvents: true},
+	}, allFlags.Flags)
+}
+
+func TestRelayProxy_cacheTTL(t *testing.T) {
+	var calls int32
+	srv := newRelayProxyMock(&calls, 0)
+	defer srv.Close()
+
+	tests := []struct {
+		name      string
+		cacheTTL  time.Duration
+		wait      time.Duration
+		wantCalls int32
+	}{
+		{name: "Cache hit", cacheTTL: time.Minute, wantCalls: 1},
+		{name: "Cache expired", cacheTTL: 10 * time.Millisecond, wait: 20 * time.Millisecond, wantCalls: 2},
+		{name: "Cache disabled", cacheTTL: -1, wantCalls: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt32(&calls, 0)
+			goff, err := ffclient.New(ffclient.Config{
+				RelayProxy: &ffclient.RelayProxy{
+					URL:      srv.URL,
+					Header:   http.Header{"Authorization": []string{"Bearer token"}},
+					CacheTTL: tt.cacheTTL,
+				},
+			})
+			assert.NoError(t, err)
+			defer goff.Close()
+
+			user := ffuser.NewUser("random-key")
+			_, err = goff.BoolVariation("test-flag", user, false)
+			assert.NoError(t, err)
+			time.Sleep(tt.wait)
+			_, err = goff.BoolVariation("test-flag", user, false)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCalls, atomic.LoadInt32(&calls))
+		})
+	}
+}
+
+func TestRelayProxy_errors(t *testing.T) {
+	var calls int32
+	srv := newRelayProxyMock(&calls, 100*time.Millisecond)
+	defer srv.Close()
+	user := ffuser.NewUser("random-key")
+
+	t.Run("Timeout", func(t *testing.T) {
+		goff, err := ffclient.New(ffclient.Config{
+			RelayProxy: &ffclient.RelayProxy{
+				URL:     srv.URL,
+				Header:  http.Header{"Authorization": []string{"Bearer token"}},
+				Timeout: 10 * time.Millisecond,
+			},
+		})
+		assert.NoError(t, err)
+		defer goff.Close()
+
+		got, err := goff.BoolVariationDetails("test-flag", user, false)
+		assert.Error(t, err)
+		assert.False(t, got.Value)
+		assert.Equal(t, ffclient.ReasonError, got.Reason)
+		assert.Equal(t, ffclient.ErrorCodeGeneral, got.ErrorCode)
+		assert.False(t, goff.AllFlagsState(user).Valid)
+	})
+
+	t.Run("Error status", func(t *testing.T) {
+		goff, err := ffclient.New(ffclient.Config{RelayProxy: &ffclient.RelayProxy{URL: srv.URL}})
+		assert.NoError(t, err)
+		defer goff.Close()
+
+		got, err := goff.StringVariation("test-flag", user, "default")
+		assert.Error(t, err)
+		assert.True(t, strings.Contains(err.Error(), "the relay proxy returned 401"), err.Error())
+		assert.Equal(t, "default", got)
+	})
+
+	t.Run("Invalid URL", func(t *testing.T) {
+		_, err := ffclient.New(ffclient.Config{RelayProxy: &ffclient.RelayProxy{URL: "not a url"}})
+		assert.Error(t, err)
+	})
+}
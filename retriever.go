@@ -1,10 +1,12 @@
 package ffclient
 
 import (
+	"context"
 	"fmt"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"io"
 	"net/http"
 	"time"
 
@@ -12,20 +14,160 @@ import (
 	"github.com/thomaspoignant/go-feature-flag/internal/retriever"
 )
 
+// Retriever is the interface to implement if you want to load your flag file from a custom source.
+//
+// Retrieve is called during the initialisation and then at every polling interval, it should return
+// the full content of the flag file in the format defined in Config.FileFormat.
+// If Retrieve returns an error, the flags already in the cache are kept.
+// Retrieve can return ErrNotModified if the file has not changed since the previous call.
+// Retrieve is never called concurrently by the same go-feature-flag instance.
 type Retriever interface {
+	Retrieve(ctx context.Context) ([]byte, error)
+}
+
+// ErrNotModified can be returned by Retrieve when the flag file has not changed since the previous call,
+// in that case the flag file is not read again and the flags are not updated.
+var ErrNotModified = retriever.ErrNotModified
+
+// InitializableRetriever is an optional interface for retrievers that need to be initialised
+// (open a connection, authenticate ...).
+// Init is called once during the initialisation of go-feature-flag, before the first call to Retrieve.
+// If Init returns an error, go-feature-flag will not start.
+type InitializableRetriever interface {
+	Retriever
+	Init(ctx context.Context) error
+}
+
+// ShutdownableRetriever is an optional interface for retrievers that need to release resources.
+// Shutdown is called once when go-feature-flag is closed, no call to Retrieve is done after it.
+type ShutdownableRetriever interface {
+	Retriever
+	Shutdown(ctx context.Context) error
+}
+
+// FileFormatRetriever is an optional interface for retrievers returning a file in a different
+// format than Config.FileFormat, it is useful when you use multiple retrievers.
+// If GetFileFormat returns an empty string, Config.FileFormat is used.
+type FileFormatRetriever interface {
+	Retriever
+	GetFileFormat() string
+}
+
+// getFileFormat returns the format of the file returned by this retriever.
+func getFileFormat(config Config, r Retriever) string {
+	if r, ok := r.(FileFormatRetriever); ok && r.GetFileFormat() != "" {
+		return r.GetFileFormat()
+	}
+	return config.FileFormat
+}
+
+// watchableRetriever is implemented by the retrievers able to detect the changes of the flag file.
+// onChange should be called every time the file changes, the returned io.Closer stops the watch.
+// A nil io.Closer means that the retriever does not watch anything.
+type watchableRetriever interface {
+	startWatching(onChange func()) (io.Closer, error)
+}
+
+// conditionalRetriever is implemented by the retrievers able to download the file only if it has changed.
+// The validators are kept by each go-feature-flag instance with the last files loaded in the cache.
+type conditionalRetriever interface {
+	retrieveIfModified(ctx context.Context, validators retriever.Validators) ([]byte, retriever.Validators, error)
+}
+
+// fileWatcherDebounce is the time we wait after the last event on a file before updating the flags.
+const fileWatcherDebounce = 100 * time.Millisecond
+
+// flagRetrieverProvider is implemented by the retrievers of the library, they create an internal
+// retriever.FlagRetriever every time we need to retrieve the flags.
+type flagRetrieverProvider interface {
 	getFlagRetriever() (retriever.FlagRetriever, error)
 }
 
+// retrieve creates the internal retriever and use it to retrieve the flags.
+func retrieve(ctx context.Context, provider flagRetrieverProvider) ([]byte, error) {
+	r, err := provider.getFlagRetriever()
+	if err != nil {
+		return nil, err
+	}
+	return r.Retrieve(ctx)
+}
+
+// retrieveIfModified creates the internal retriever and use it to retrieve the flags if they have changed
+// since the version identified by the validators.
+func retrieveIfModified(ctx context.Context, provider flagRetrieverProvider,
+	validators retriever.Validators) ([]byte, retriever.Validators, error) {
+	r, err := provider.getFlagRetriever()
+	if err != nil {
+		return nil, retriever.Validators{}, err
+	}
+	if r, ok := r.(retriever.ConditionalRetriever); ok {
+		return r.RetrieveIfModified(ctx, validators)
+	}
+	content, err := r.Retrieve(ctx)
+	return content, retriever.Validators{}, err
+}
+
 // FileRetriever is a configuration struct for a local flat file.
 type FileRetriever struct {
 	Path string
+
+	// Watch (optional) if true, the file is watched and the flags are updated as soon as the file changes.
+	// The polling is still used as a fallback.
+	// Default: false
+	Watch bool
+
+	// FileFormat (optional) is the format of the file, it overrides Config.FileFormat for this retriever.
+	FileFormat string
+}
+
+// Retrieve is reading the file and return the content.
+func (r *FileRetriever) Retrieve(ctx context.Context) ([]byte, error) {
+	return retrieve(ctx, r)
+}
+
+// GetFileFormat returns the format of the file, empty if not set.
+func (r *FileRetriever) GetFileFormat() string {
+	return r.FileFormat
 }
 
-// nolint: unused
 func (r *FileRetriever) getFlagRetriever() (retriever.FlagRetriever, error) { // nolint: unparam
 	return retriever.NewLocalRetriever(r.Path), nil
 }
 
+// nolint: unused
+func (r *FileRetriever) startWatching(onChange func()) (io.Closer, error) {
+	if !r.Watch {
+		return nil, nil
+	}
+	return retriever.NewFileWatcher(r.Path, fileWatcherDebounce, onChange)
+}
+
+// DirectoryRetriever is a configuration struct for a local directory containing multiple flag files.
+// The format of each file is detected with its extension (.yaml, .yml, .json or .toml), the other
+// files are ignored. If a flag is defined in more than one file, the retriever returns an error.
+type DirectoryRetriever struct {
+	// Path is the location of the directory.
+	Path string
+
+	// Pattern (optional) is a glob pattern to select the files of the directory (ex: "*.yaml").
+	// Default: all the files of the directory.
+	Pattern string
+}
+
+// Retrieve is reading all the files of the directory and return them merged in one file.
+func (r *DirectoryRetriever) Retrieve(ctx context.Context) ([]byte, error) {
+	return retrieve(ctx, r)
+}
+
+// GetFileFormat returns the format of the merged file, it is always JSON.
+func (r *DirectoryRetriever) GetFileFormat() string {
+	return "json"
+}
+
+func (r *DirectoryRetriever) getFlagRetriever() (retriever.FlagRetriever, error) { // nolint: unparam
+	return retriever.NewDirectoryRetriever(r.Path, r.Pattern), nil
+}
+
 // HTTPRetriever is a configuration struct for an HTTP endpoint retriever.
 type HTTPRetriever struct {
 	URL     string
@@ -33,9 +175,27 @@ type HTTPRetriever struct {
 	Body    string
 	Header  http.Header
 	Timeout time.Duration
+
+	// FileFormat (optional) is the format of the file, it overrides Config.FileFormat for this retriever.
+	FileFormat string
+}
+
+// Retrieve is calling the HTTP endpoint and return the content.
+func (r *HTTPRetriever) Retrieve(ctx context.Context) ([]byte, error) {
+	return retrieve(ctx, r)
+}
+
+// GetFileFormat returns the format of the file, empty if not set.
+func (r *HTTPRetriever) GetFileFormat() string {
+	return r.FileFormat
 }
 
 // nolint: unused
+func (r *HTTPRetriever) retrieveIfModified(ctx context.Context,
+	validators retriever.Validators) ([]byte, retriever.Validators, error) {
+	return retrieveIfModified(ctx, r, validators)
+}
+
 func (r *HTTPRetriever) getFlagRetriever() (retriever.FlagRetriever, error) {
 	timeout := r.Timeout
 	if timeout <= 0 {
@@ -62,9 +222,21 @@ type S3Retriever struct {
 	// AwsConfig is the AWS SDK configuration object we will use to
 	// download your feature flag configuration file.
 	AwsConfig aws.Config
+
+	// FileFormat (optional) is the format of the file, it overrides Config.FileFormat for this retriever.
+	FileFormat string
+}
+
+// Retrieve is downloading the file from S3 and return the content.
+func (r *S3Retriever) Retrieve(ctx context.Context) ([]byte, error) {
+	return retrieve(ctx, r)
+}
+
+// GetFileFormat returns the format of the file, empty if not set.
+func (r *S3Retriever) GetFileFormat() string {
+	return r.FileFormat
 }
 
-// nolint: unused
 func (r *S3Retriever) getFlagRetriever() (retriever.FlagRetriever, error) {
 	// Create an AWS session
 	sess, err := session.NewSession(&r.AwsConfig)
@@ -88,9 +260,25 @@ type GithubRetriever struct {
 	FilePath       string
 	GithubToken    string
 	Timeout        time.Duration // default is 10 seconds
+	FileFormat     string        // default is Config.FileFormat
+}
+
+// Retrieve is downloading the file from GitHub and return the content.
+func (r *GithubRetriever) Retrieve(ctx context.Context) ([]byte, error) {
+	return retrieve(ctx, r)
+}
+
+// GetFileFormat returns the format of the file, empty if not set.
+func (r *GithubRetriever) GetFileFormat() string {
+	return r.FileFormat
 }
 
 // nolint: unused
+func (r *GithubRetriever) retrieveIfModified(ctx context.Context,
+	validators retriever.Validators) ([]byte, retriever.Validators, error) {
+	return retrieveIfModified(ctx, r, validators)
+}
+
 func (r *GithubRetriever) getFlagRetriever() (retriever.FlagRetriever, error) {
 	// default branch is main
 	branch := r.Branch
@@ -0,0 +1,64 @@
+package testutils
+
+import (
+	"context"
+	"sync"
+)
+
+// MockRetriever is a retriever returning the content of the field Content,
+// it can be used to test go-feature-flag without any external file.
+type MockRetriever struct {
+	Content     []byte
+	FileFormat  string
+	Err         error
+	InitErr     error
+	ShutdownErr error
+
+	InitCalled     bool
+	ShutdownCalled bool
+	RetrieveCalls  int
+
+	mutex sync.Mutex
+}
+
+func (m *MockRetriever) Init(ctx context.Context) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.InitCalled = true
+	return m.InitErr
+}
+
+func (m *MockRetriever) Retrieve(ctx context.Context) ([]byte, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.RetrieveCalls++
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Content, nil
+}
+
+func (m *MockRetriever) Shutdown(ctx context.Context) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.ShutdownCalled = true
+	return m.ShutdownErr
+}
+
+func (m *MockRetriever) GetFileFormat() string {
+	return m.FileFormat
+}
+
+// SetErr changes the error returned by the next calls to Retrieve.
+func (m *MockRetriever) SetErr(err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.Err = err
+}
+
+// SetContent changes the content returned by the next calls to Retrieve.
+func (m *MockRetriever) SetContent(content []byte) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.Content = content
+}
@@ -1,6 +1,7 @@
 package ffclient
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/thomaspoignant/go-feature-flag/ffuser"
@@ -9,7 +10,7 @@ import (
 )
 
 const errorFlagNotAvailable = "flag %v is not present or disabled"
-const errorWrongVariation = "wrong variation used for flag %v"
+const errorEvaluation = "impossible to evaluate flag %v"
 
 // BoolVariation return the value of the flag in boolean.
 // An error is return if you don't have init the library before calling the function.
@@ -54,25 +55,67 @@ func JSONVariation(
 	return ff.JSONVariation(flagKey, user, defaultValue)
 }
 
+// BoolVariationDetails return the value of the flag in boolean with the details of the evaluation.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+func BoolVariationDetails(flagKey string, user ffuser.User, defaultValue bool) (BoolEvaluationDetails, error) {
+	return ff.BoolVariationDetails(flagKey, user, defaultValue)
+}
+
+// IntVariationDetails return the value of the flag in int with the details of the evaluation.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+func IntVariationDetails(flagKey string, user ffuser.User, defaultValue int) (IntEvaluationDetails, error) {
+	return ff.IntVariationDetails(flagKey, user, defaultValue)
+}
+
+// Float64VariationDetails return the value of the flag in float64 with the details of the evaluation.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+func Float64VariationDetails(
+	flagKey string, user ffuser.User, defaultValue float64) (Float64EvaluationDetails, error) {
+	return ff.Float64VariationDetails(flagKey, user, defaultValue)
+}
+
+// StringVariationDetails return the value of the flag in string with the details of the evaluation.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+func StringVariationDetails(
+	flagKey string, user ffuser.User, defaultValue string) (StringEvaluationDetails, error) {
+	return ff.StringVariationDetails(flagKey, user, defaultValue)
+}
+
+// JSONArrayVariationDetails return the value of the flag in []interface{} with the details of the evaluation.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+func JSONArrayVariationDetails(
+	flagKey string, user ffuser.User, defaultValue []interface{}) (JSONArrayEvaluationDetails, error) {
+	return ff.JSONArrayVariationDetails(flagKey, user, defaultValue)
+}
+
+// JSONVariationDetails return the value of the flag in map[string]interface{} with the details of the evaluation.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+func JSONVariationDetails(
+	flagKey string, user ffuser.User, defaultValue map[string]interface{}) (JSONEvaluationDetails, error) {
+	return ff.JSONVariationDetails(flagKey, user, defaultValue)
+}
+
+// RawVariationDetails return the value of the flag without any conversion with the details of the evaluation,
+// use it if you don't know the type of the flag (ex: to serve the flags to another service).
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+func RawVariationDetails(
+	flagKey string, user ffuser.User, defaultValue interface{}) (RawEvaluationDetails, error) {
+	return ff.RawVariationDetails(flagKey, user, defaultValue)
+}
+
 // BoolVariation return the value of the flag in boolean.
 // An error is return if you don't have init the library before calling the function.
 // If the key does not exist we return the default value.
 // Note: Use this function only if you are using multiple go-feature-flag instances.
 func (g *GoFeatureFlag) BoolVariation(flagKey string, user ffuser.User, defaultValue bool) (bool, error) {
-	flag, err := g.getFlagFromCache(flagKey)
-	if err != nil {
-		g.notifyVariation(flagKey, flag, user, defaultValue, model.VariationSDKDefault, true)
-		return defaultValue, err
-	}
-
-	flagValue, variationType := flag.Value(flagKey, user)
-	res, ok := flagValue.(bool)
-	if !ok {
-		g.notifyVariation(flagKey, flag, user, defaultValue, model.VariationSDKDefault, true)
-		return defaultValue, fmt.Errorf(errorWrongVariation, flagKey)
-	}
-	g.notifyVariation(flagKey, flag, user, res, variationType, false)
-	return res, nil
+	return g.BoolVariationCtx(context.Background(), flagKey, user, defaultValue)
 }
 
 // IntVariation return the value of the flag in int.
@@ -80,27 +123,7 @@ func (g *GoFeatureFlag) BoolVariation(flagKey string, user ffuser.User, defaultV
 // If the key does not exist we return the default value.
 // Note: Use this function only if you are using multiple go-feature-flag instances.
 func (g *GoFeatureFlag) IntVariation(flagKey string, user ffuser.User, defaultValue int) (int, error) {
-	flag, err := g.getFlagFromCache(flagKey)
-	if err != nil {
-		g.notifyVariation(flagKey, flag, user, defaultValue, model.VariationSDKDefault, true)
-		return defaultValue, err
-	}
-
-	flagValue, variationType := flag.Value(flagKey, user)
-	res, ok := flagValue.(int)
-	if !ok {
-		// if this is a float64 we convert it to int
-		if resFloat, okFloat := flagValue.(float64); okFloat {
-			intRes := int(resFloat)
-			g.notifyVariation(flagKey, flag, user, intRes, variationType, false)
-			return intRes, nil
-		}
-
-		g.notifyVariation(flagKey, flag, user, defaultValue, model.VariationSDKDefault, true)
-		return defaultValue, fmt.Errorf(errorWrongVariation, flagKey)
-	}
-	g.notifyVariation(flagKey, flag, user, res, variationType, false)
-	return res, nil
+	return g.IntVariationCtx(context.Background(), flagKey, user, defaultValue)
 }
 
 // Float64Variation return the value of the flag in float64.
@@ -108,20 +131,7 @@ func (g *GoFeatureFlag) IntVariation(flagKey string, user ffuser.User, defaultVa
 // If the key does not exist we return the default value.
 // Note: Use this function only if you are using multiple go-feature-flag instances.
 func (g *GoFeatureFlag) Float64Variation(flagKey string, user ffuser.User, defaultValue float64) (float64, error) {
-	flag, err := g.getFlagFromCache(flagKey)
-	if err != nil {
-		g.notifyVariation(flagKey, flag, user, defaultValue, model.VariationSDKDefault, true)
-		return defaultValue, err
-	}
-
-	flagValue, variationType := flag.Value(flagKey, user)
-	res, ok := flagValue.(float64)
-	if !ok {
-		g.notifyVariation(flagKey, flag, user, defaultValue, model.VariationSDKDefault, true)
-		return defaultValue, fmt.Errorf(errorWrongVariation, flagKey)
-	}
-	g.notifyVariation(flagKey, flag, user, res, variationType, false)
-	return res, nil
+	return g.Float64VariationCtx(context.Background(), flagKey, user, defaultValue)
 }
 
 // StringVariation return the value of the flag in string.
@@ -129,20 +139,7 @@ func (g *GoFeatureFlag) Float64Variation(flagKey string, user ffuser.User, defau
 // If the key does not exist we return the default value.
 // Note: Use this function only if you are using multiple go-feature-flag instances.
 func (g *GoFeatureFlag) StringVariation(flagKey string, user ffuser.User, defaultValue string) (string, error) {
-	flag, err := g.getFlagFromCache(flagKey)
-	if err != nil {
-		g.notifyVariation(flagKey, flag, user, defaultValue, model.VariationSDKDefault, true)
-		return defaultValue, err
-	}
-
-	flagValue, variationType := flag.Value(flagKey, user)
-	res, ok := flagValue.(string)
-	if !ok {
-		g.notifyVariation(flagKey, flag, user, defaultValue, model.VariationSDKDefault, true)
-		return defaultValue, fmt.Errorf(errorWrongVariation, flagKey)
-	}
-	g.notifyVariation(flagKey, flag, user, res, variationType, false)
-	return res, nil
+	return g.StringVariationCtx(context.Background(), flagKey, user, defaultValue)
 }
 
 // JSONArrayVariation return the value of the flag in []interface{}.
@@ -151,20 +148,7 @@ func (g *GoFeatureFlag) StringVariation(flagKey string, user ffuser.User, defaul
 // Note: Use this function only if you are using multiple go-feature-flag instances.
 func (g *GoFeatureFlag) JSONArrayVariation(
 	flagKey string, user ffuser.User, defaultValue []interface{}) ([]interface{}, error) {
-	flag, err := g.getFlagFromCache(flagKey)
-	if err != nil {
-		g.notifyVariation(flagKey, flag, user, defaultValue, model.VariationSDKDefault, true)
-		return defaultValue, err
-	}
-
-	flagValue, variationType := flag.Value(flagKey, user)
-	res, ok := flagValue.([]interface{})
-	if !ok {
-		g.notifyVariation(flagKey, flag, user, defaultValue, model.VariationSDKDefault, true)
-		return defaultValue, fmt.Errorf(errorWrongVariation, flagKey)
-	}
-	g.notifyVariation(flagKey, flag, user, res, variationType, false)
-	return res, nil
+	return g.JSONArrayVariationCtx(context.Background(), flagKey, user, defaultValue)
 }
 
 // JSONVariation return the value of the flag in map[string]interface{}.
@@ -173,43 +157,154 @@ func (g *GoFeatureFlag) JSONArrayVariation(
 // Note: Use this function only if you are using multiple go-feature-flag instances.
 func (g *GoFeatureFlag) JSONVariation(
 	flagKey string, user ffuser.User, defaultValue map[string]interface{}) (map[string]interface{}, error) {
-	flag, err := g.getFlagFromCache(flagKey)
+	return g.JSONVariationCtx(context.Background(), flagKey, user, defaultValue)
+}
+
+// BoolVariationDetails return the value of the flag in boolean with the details of the evaluation.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) BoolVariationDetails(
+	flagKey string, user ffuser.User, defaultValue bool) (BoolEvaluationDetails, error) {
+	return g.BoolVariationDetailsCtx(context.Background(), flagKey, user, defaultValue)
+}
+
+// IntVariationDetails return the value of the flag in int with the details of the evaluation.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) IntVariationDetails(
+	flagKey string, user ffuser.User, defaultValue int) (IntEvaluationDetails, error) {
+	return g.IntVariationDetailsCtx(context.Background(), flagKey, user, defaultValue)
+}
+
+// Float64VariationDetails return the value of the flag in float64 with the details of the evaluation.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) Float64VariationDetails(
+	flagKey string, user ffuser.User, defaultValue float64) (Float64EvaluationDetails, error) {
+	return g.Float64VariationDetailsCtx(context.Background(), flagKey, user, defaultValue)
+}
+
+// StringVariationDetails return the value of the flag in string with the details of the evaluation.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) StringVariationDetails(
+	flagKey string, user ffuser.User, defaultValue string) (StringEvaluationDetails, error) {
+	return g.StringVariationDetailsCtx(context.Background(), flagKey, user, defaultValue)
+}
+
+// JSONArrayVariationDetails return the value of the flag in []interface{} with the details of the evaluation.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) JSONArrayVariationDetails(
+	flagKey string, user ffuser.User, defaultValue []interface{}) (JSONArrayEvaluationDetails, error) {
+	return g.JSONArrayVariationDetailsCtx(context.Background(), flagKey, user, defaultValue)
+}
+
+// JSONVariationDetails return the value of the flag in map[string]interface{} with the details of the evaluation.
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) JSONVariationDetails(
+	flagKey string, user ffuser.User, defaultValue map[string]interface{}) (JSONEvaluationDetails, error) {
+	return g.JSONVariationDetailsCtx(context.Background(), flagKey, user, defaultValue)
+}
+
+// RawVariationDetails return the value of the flag without any conversion with the details of the evaluation,
+// use it if you don't know the type of the flag (ex: to serve the flags to another service).
+// An error is return if you don't have init the library before calling the function.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) RawVariationDetails(
+	flagKey string, user ffuser.User, defaultValue interface{}) (RawEvaluationDetails, error) {
+	return g.RawVariationDetailsCtx(context.Background(), flagKey, user, defaultValue)
+}
+
+// evaluate is the common part of all the variation functions, it evaluates the flag for the user
+// and converts the value with the convert function, the hooks of the configuration are called around it.
+// If something goes wrong we return the sdkDefault value and the details of the error.
+func (g *GoFeatureFlag) evaluate(ctx context.Context, flagKey string, user ffuser.User, sdkDefault interface{},
+	convert func(interface{}) (interface{}, bool)) (interface{}, EvaluationDetails, error) {
+	value, details, err := g.evaluateWithHooks(ctx, flagKey, user,
+		func(user ffuser.User) (interface{}, EvaluationDetails, error) {
+			return g.evaluateFlag(ctx, flagKey, user, sdkDefault, convert)
+		}, sdkDefault)
+	metricsKey := flagKey
+	if details.ErrorCode == ErrorCodeFlagNotFound {
+		// the keys of the unknown flags come from the callers, we don't want a metric for each of them.
+		metricsKey = UnknownFlagMetricsKey
+	}
+	g.metricsRecorder().RecordEvaluation(metricsKey, details.Variation, string(details.Reason))
+	return value, details, err
+}
+
+// evaluateFlag evaluates the flag for the user and converts the value with the convert function.
+func (g *GoFeatureFlag) evaluateFlag(ctx context.Context, flagKey string, user ffuser.User, sdkDefault interface{},
+	convert func(interface{}) (interface{}, bool)) (interface{}, EvaluationDetails, error) {
+	// the context is only used for I/O (relay proxy, data exporter), the flags of the cache are always evaluated.
+	if g.remote != nil {
+		value, details, err := g.remote.evaluate(ctx, flagKey, user, sdkDefault)
+		if err != nil {
+			return sdkDefault, details, err
+		}
+		res, ok := convert(value)
+		if !ok {
+			return sdkDefault, SDKDefaultDetails(ReasonTypeMismatch, ErrorCodeTypeMismatch),
+				WrongVariationError(flagKey)
+		}
+		return res, details, nil
+	}
+
+	flag, err := g.cache.GetFlag(flagKey)
 	if err != nil {
-		g.notifyVariation(flagKey, flag, user, defaultValue, model.VariationSDKDefault, true)
-		return defaultValue, err
+		details := SDKDefaultDetails(ReasonFlagNotFound, ErrorCodeFlagNotFound)
+		g.notifyVariation(ctx, flagKey, flag, user, sdkDefault, details, true)
+		return sdkDefault, details, fmt.Errorf(errorFlagNotAvailable, flagKey)
 	}
 
-	flagValue, variationType := flag.Value(flagKey, user)
-	res, ok := flagValue.(map[string]interface{})
+	if flag.GetDisable() {
+		details := SDKDefaultDetails(ReasonDisabled, "")
+		g.notifyVariation(ctx, flagKey, flag, user, sdkDefault, details, true)
+		return sdkDefault, details, fmt.Errorf(errorFlagNotAvailable, flagKey)
+	}
+
+	flagValue, resolution := flag.Evaluate(flagKey, user, g.cache)
+	if resolution.Reason == model.ReasonError {
+		details := SDKDefaultDetails(ReasonError, ErrorCodeGeneral)
+		g.notifyVariation(ctx, flagKey, flag, user, sdkDefault, details, true)
+		return sdkDefault, details, fmt.Errorf(errorEvaluation, flagKey)
+	}
+	res, ok := convert(flagValue)
 	if !ok {
-		g.notifyVariation(flagKey, flag, user, defaultValue, model.VariationSDKDefault, true)
-		return defaultValue, fmt.Errorf(errorWrongVariation, flagKey)
+		details := SDKDefaultDetails(ReasonTypeMismatch, ErrorCodeTypeMismatch)
+		g.notifyVariation(ctx, flagKey, flag, user, sdkDefault, details, true)
+		return sdkDefault, details, WrongVariationError(flagKey)
 	}
-	g.notifyVariation(flagKey, flag, user, res, variationType, false)
-	g.notifyVariation(flagKey, flag, user, res, variationType, false)
-	return res, nil
+
+	details := EvaluationDetails{
+		Variation: string(resolution.Variation),
+		Reason:    resolution.Reason,
+		RuleName:  resolution.RuleName,
+	}
+	g.notifyVariation(ctx, flagKey, flag, user, res, details, false)
+	return res, details, nil
 }
 
 // notifyVariation is logging the evaluation result for a flag
 // if no logger is provided in the configuration we are not logging anything.
-func (g *GoFeatureFlag) notifyVariation(
-	flagKey string, flag model.Flag, user ffuser.User, value interface{}, variationType model.VariationType, failed bool) {
+func (g *GoFeatureFlag) notifyVariation(ctx context.Context,
+	flagKey string, flag model.Flag, user ffuser.User, value interface{}, details EvaluationDetails, failed bool) {
 	if flag.GetTrackEvents() {
-		event := exporter.NewFeatureEvent(user, flagKey, flag, value, variationType, failed)
+		event := exporter.NewFeatureEvent(user, flagKey, flag, value,
+			model.VariationType(details.Variation), details.Reason, failed)
 
 		// Add event in the exporter
 		if g.dataExporter != nil {
-			g.dataExporter.AddEvent(event)
+			g.dataExporter.AddEventCtx(ctx, event)
 		}
 	}
 }
-
-// getFlagFromCache try to get the flag from the cache.
-// It returns an error if the cache is not init or if the flag is not present or disabled.
-func (g *GoFeatureFlag) getFlagFromCache(flagKey string) (model.Flag, error) {
-	flag, err := g.cache.GetFlag(flagKey)
-	if err != nil || flag.GetDisable() {
-		return flag, fmt.Errorf(errorFlagNotAvailable, flagKey)
-	}
-	return flag, nil
-}
@@ -0,0 +1,295 @@
+package ffclient
+
+import (
+	"context"
+
+	"github.com/thomaspoignant/go-feature-flag/ffuser"
+)
+
+// BoolVariationCtx return the value of the flag in boolean.
+// An error is return if you don't have init the library before calling the function.
+// The context is passed to the hooks, the data exporter and the relay proxy, it never cancels a local evaluation.
+// If the key does not exist we return the default value.
+func BoolVariationCtx(ctx context.Context, flagKey string, user ffuser.User, defaultValue bool) (bool, error) {
+	return ff.BoolVariationCtx(ctx, flagKey, user, defaultValue)
+}
+
+// IntVariationCtx return the value of the flag in int.
+// An error is return if you don't have init the library before calling the function.
+// The context is passed to the hooks, the data exporter and the relay proxy, it never cancels a local evaluation.
+// If the key does not exist we return the default value.
+func IntVariationCtx(ctx context.Context, flagKey string, user ffuser.User, defaultValue int) (int, error) {
+	return ff.IntVariationCtx(ctx, flagKey, user, defaultValue)
+}
+
+// Float64VariationCtx return the value of the flag in float64.
+// An error is return if you don't have init the library before calling the function.
+// The context is passed to the hooks, the data exporter and the relay proxy, it never cancels a local evaluation.
+// If the key does not exist we return the default value.
+func Float64VariationCtx(ctx context.Context, flagKey string, user ffuser.User, defaultValue float64) (float64, error) {
+	return ff.Float64VariationCtx(ctx, flagKey, user, defaultValue)
+}
+
+// StringVariationCtx return the value of the flag in string.
+// An error is return if you don't have init the library before calling the function.
+// The context is passed to the hooks, the data exporter and the relay proxy, it never cancels a local evaluation.
+// If the key does not exist we return the default value.
+func StringVariationCtx(ctx context.Context, flagKey string, user ffuser.User, defaultValue string) (string, error) {
+	return ff.StringVariationCtx(ctx, flagKey, user, defaultValue)
+}
+
+// JSONArrayVariationCtx return the value of the flag in []interface{}.
+// An error is return if you don't have init the library before calling the function.
+// The context is passed to the hooks, the data exporter and the relay proxy, it never cancels a local evaluation.
+// If the key does not exist we return the default value.
+func JSONArrayVariationCtx(
+	ctx context.Context, flagKey string, user ffuser.User, defaultValue []interface{}) ([]interface{}, error) {
+	return ff.JSONArrayVariationCtx(ctx, flagKey, user, defaultValue)
+}
+
+// JSONVariationCtx return the value of the flag in map[string]interface{}.
+// An error is return if you don't have init the library before calling the function.
+// The context is passed to the hooks, the data exporter and the relay proxy, it never cancels a local evaluation.
+// If the key does not exist we return the default value.
+func JSONVariationCtx(
+	ctx context.Context, flagKey string, user ffuser.User,
+	defaultValue map[string]interface{}) (map[string]interface{}, error) {
+	return ff.JSONVariationCtx(ctx, flagKey, user, defaultValue)
+}
+
+// BoolVariationDetailsCtx return the value of the flag in boolean with the details of the evaluation.
+// An error is return if you don't have init the library before calling the function.
+// The context is passed to the hooks, the data exporter and the relay proxy, it never cancels a local evaluation.
+// If the key does not exist we return the default value.
+func BoolVariationDetailsCtx(
+	ctx context.Context, flagKey string, user ffuser.User, defaultValue bool) (BoolEvaluationDetails, error) {
+	return ff.BoolVariationDetailsCtx(ctx, flagKey, user, defaultValue)
+}
+
+// IntVariationDetailsCtx return the value of the flag in int with the details of the evaluation.
+// An error is return if you don't have init the library before calling the function.
+// The context is passed to the hooks, the data exporter and the relay proxy, it never cancels a local evaluation.
+// If the key does not exist we return the default value.
+func IntVariationDetailsCtx(
+	ctx context.Context, flagKey string, user ffuser.User, defaultValue int) (IntEvaluationDetails, error) {
+	return ff.IntVariationDetailsCtx(ctx, flagKey, user, defaultValue)
+}
+
+// Float64VariationDetailsCtx return the value of the flag in float64 with the details of the evaluation.
+// An error is return if you don't have init the library before calling the function.
+// The context is passed to the hooks, the data exporter and the relay proxy, it never cancels a local evaluation.
+// If the key does not exist we return the default value.
+func Float64VariationDetailsCtx(
+	ctx context.Context, flagKey string, user ffuser.User, defaultValue float64) (Float64EvaluationDetails, error) {
+	return ff.Float64VariationDetailsCtx(ctx, flagKey, user, defaultValue)
+}
+
+// StringVariationDetailsCtx return the value of the flag in string with the details of the evaluation.
+// An error is return if you don't have init the library before calling the function.
+// The context is passed to the hooks, the data exporter and the relay proxy, it never cancels a local evaluation.
+// If the key does not exist we return the default value.
+func StringVariationDetailsCtx(
+	ctx context.Context, flagKey string, user ffuser.User, defaultValue string) (StringEvaluationDetails, error) {
+	return ff.StringVariationDetailsCtx(ctx, flagKey, user, defaultValue)
+}
+
+// JSONArrayVariationDetailsCtx return the value of the flag in []interface{} with the details of the evaluation.
+// An error is return if you don't have init the library before calling the function.
+// The context is passed to the hooks, the data exporter and the relay proxy, it never cancels a local evaluation.
+// If the key does not exist we return the default value.
+func JSONArrayVariationDetailsCtx(
+	ctx context.Context, flagKey string, user ffuser.User,
+	defaultValue []interface{}) (JSONArrayEvaluationDetails, error) {
+	return ff.JSONArrayVariationDetailsCtx(ctx, flagKey, user, defaultValue)
+}
+
+// JSONVariationDetailsCtx return the value of the flag in map[string]interface{} with the details of the evaluation.
+// An error is return if you don't have init the library before calling the function.
+// The context is passed to the hooks, the data exporter and the relay proxy, it never cancels a local evaluation.
+// If the key does not exist we return the default value.
+func JSONVariationDetailsCtx(
+	ctx context.Context, flagKey string, user ffuser.User,
+	defaultValue map[string]interface{}) (JSONEvaluationDetails, error) {
+	return ff.JSONVariationDetailsCtx(ctx, flagKey, user, defaultValue)
+}
+
+// RawVariationDetailsCtx return the value of the flag without any conversion with the details of the evaluation,
+// use it if you don't know the type of the flag (ex: to serve the flags to another service).
+// An error is return if you don't have init the library before calling the function.
+// The context is passed to the hooks, the data exporter and the relay proxy, it never cancels a local evaluation.
+// If the key does not exist we return the default value.
+func RawVariationDetailsCtx(
+	ctx context.Context, flagKey string, user ffuser.User, defaultValue interface{}) (RawEvaluationDetails, error) {
+	return ff.RawVariationDetailsCtx(ctx, flagKey, user, defaultValue)
+}
+
+// BoolVariationCtx return the value of the flag in boolean.
+// An error is return if you don't have init the library before calling the function.
+// The context is passed to the hooks, the data exporter and the relay proxy, it never cancels a local evaluation.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) BoolVariationCtx(
+	ctx context.Context, flagKey string, user ffuser.User, defaultValue bool) (bool, error) {
+	res, err := g.BoolVariationDetailsCtx(ctx, flagKey, user, defaultValue)
+	return res.Value, err
+}
+
+// IntVariationCtx return the value of the flag in int.
+// An error is return if you don't have init the library before calling the function.
+// The context is passed to the hooks, the data exporter and the relay proxy, it never cancels a local evaluation.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) IntVariationCtx(
+	ctx context.Context, flagKey string, user ffuser.User, defaultValue int) (int, error) {
+	res, err := g.IntVariationDetailsCtx(ctx, flagKey, user, defaultValue)
+	return res.Value, err
+}
+
+// Float64VariationCtx return the value of the flag in float64.
+// An error is return if you don't have init the library before calling the function.
+// The context is passed to the hooks, the data exporter and the relay proxy, it never cancels a local evaluation.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) Float64VariationCtx(
+	ctx context.Context, flagKey string, user ffuser.User, defaultValue float64) (float64, error) {
+	res, err := g.Float64VariationDetailsCtx(ctx, flagKey, user, defaultValue)
+	return res.Value, err
+}
+
+// StringVariationCtx return the value of the flag in string.
+// An error is return if you don't have init the library before calling the function.
+// The context is passed to the hooks, the data exporter and the relay proxy, it never cancels a local evaluation.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) StringVariationCtx(
+	ctx context.Context, flagKey string, user ffuser.User, defaultValue string) (string, error) {
+	res, err := g.StringVariationDetailsCtx(ctx, flagKey, user, defaultValue)
+	return res.Value, err
+}
+
+// JSONArrayVariationCtx return the value of the flag in []interface{}.
+// An error is return if you don't have init the library before calling the function.
+// The context is passed to the hooks, the data exporter and the relay proxy, it never cancels a local evaluation.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) JSONArrayVariationCtx(
+	ctx context.Context, flagKey string, user ffuser.User, defaultValue []interface{}) ([]interface{}, error) {
+	res, err := g.JSONArrayVariationDetailsCtx(ctx, flagKey, user, defaultValue)
+	return res.Value, err
+}
+
+// JSONVariationCtx return the value of the flag in map[string]interface{}.
+// An error is return if you don't have init the library before calling the function.
+// The context is passed to the hooks, the data exporter and the relay proxy, it never cancels a local evaluation.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) JSONVariationCtx(
+	ctx context.Context, flagKey string, user ffuser.User,
+	defaultValue map[string]interface{}) (map[string]interface{}, error) {
+	res, err := g.JSONVariationDetailsCtx(ctx, flagKey, user, defaultValue)
+	return res.Value, err
+}
+
+// BoolVariationDetailsCtx return the value of the flag in boolean with the details of the evaluation.
+// An error is return if you don't have init the library before calling the function.
+// The context is passed to the hooks, the data exporter and the relay proxy, it never cancels a local evaluation.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) BoolVariationDetailsCtx(
+	ctx context.Context, flagKey string, user ffuser.User, defaultValue bool) (BoolEvaluationDetails, error) {
+	value, details, err := g.evaluate(ctx, flagKey, user, defaultValue, func(v interface{}) (interface{}, bool) {
+		res, ok := v.(bool)
+		return res, ok
+	})
+	return BoolEvaluationDetails{Value: value.(bool), EvaluationDetails: details}, err
+}
+
+// IntVariationDetailsCtx return the value of the flag in int with the details of the evaluation.
+// An error is return if you don't have init the library before calling the function.
+// The context is passed to the hooks, the data exporter and the relay proxy, it never cancels a local evaluation.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) IntVariationDetailsCtx(
+	ctx context.Context, flagKey string, user ffuser.User, defaultValue int) (IntEvaluationDetails, error) {
+	value, details, err := g.evaluate(ctx, flagKey, user, defaultValue, func(v interface{}) (interface{}, bool) {
+		// if this is a float64 we convert it to int
+		if resFloat, okFloat := v.(float64); okFloat {
+			return int(resFloat), true
+		}
+		res, ok := v.(int)
+		return res, ok
+	})
+	return IntEvaluationDetails{Value: value.(int), EvaluationDetails: details}, err
+}
+
+// Float64VariationDetailsCtx return the value of the flag in float64 with the details of the evaluation.
+// An error is return if you don't have init the library before calling the function.
+// The context is passed to the hooks, the data exporter and the relay proxy, it never cancels a local evaluation.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) Float64VariationDetailsCtx(
+	ctx context.Context, flagKey string, user ffuser.User, defaultValue float64) (Float64EvaluationDetails, error) {
+	value, details, err := g.evaluate(ctx, flagKey, user, defaultValue, func(v interface{}) (interface{}, bool) {
+		res, ok := v.(float64)
+		return res, ok
+	})
+	return Float64EvaluationDetails{Value: value.(float64), EvaluationDetails: details}, err
+}
+
+// StringVariationDetailsCtx return the value of the flag in string with the details of the evaluation.
+// An error is return if you don't have init the library before calling the function.
+// The context is passed to the hooks, the data exporter and the relay proxy, it never cancels a local evaluation.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) StringVariationDetailsCtx(
+	ctx context.Context, flagKey string, user ffuser.User, defaultValue string) (StringEvaluationDetails, error) {
+	value, details, err := g.evaluate(ctx, flagKey, user, defaultValue, func(v interface{}) (interface{}, bool) {
+		res, ok := v.(string)
+		return res, ok
+	})
+	return StringEvaluationDetails{Value: value.(string), EvaluationDetails: details}, err
+}
+
+// JSONArrayVariationDetailsCtx return the value of the flag in []interface{} with the details of the evaluation.
+// An error is return if you don't have init the library before calling the function.
+// The context is passed to the hooks, the data exporter and the relay proxy, it never cancels a local evaluation.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) JSONArrayVariationDetailsCtx(
+	ctx context.Context, flagKey string, user ffuser.User,
+	defaultValue []interface{}) (JSONArrayEvaluationDetails, error) {
+	value, details, err := g.evaluate(ctx, flagKey, user, defaultValue, func(v interface{}) (interface{}, bool) {
+		res, ok := v.([]interface{})
+		return res, ok
+	})
+	return JSONArrayEvaluationDetails{Value: value.([]interface{}), EvaluationDetails: details}, err
+}
+
+// JSONVariationDetailsCtx return the value of the flag in map[string]interface{} with the details of the evaluation.
+// An error is return if you don't have init the library before calling the function.
+// The context is passed to the hooks, the data exporter and the relay proxy, it never cancels a local evaluation.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) JSONVariationDetailsCtx(
+	ctx context.Context, flagKey string, user ffuser.User,
+	defaultValue map[string]interface{}) (JSONEvaluationDetails, error) {
+	value, details, err := g.evaluate(ctx, flagKey, user, defaultValue, func(v interface{}) (interface{}, bool) {
+		res, ok := v.(map[string]interface{})
+		return res, ok
+	})
+	return JSONEvaluationDetails{Value: value.(map[string]interface{}), EvaluationDetails: details}, err
+}
+
+// RawVariationDetailsCtx return the value of the flag without any conversion with the details of the evaluation,
+// use it if you don't know the type of the flag (ex: to serve the flags to another service).
+// An error is return if you don't have init the library before calling the function.
+// The context is passed to the hooks, the data exporter and the relay proxy, it never cancels a local evaluation.
+// If the key does not exist we return the default value.
+// Note: Use this function only if you are using multiple go-feature-flag instances.
+func (g *GoFeatureFlag) RawVariationDetailsCtx(
+	ctx context.Context, flagKey string, user ffuser.User, defaultValue interface{}) (RawEvaluationDetails, error) {
+	value, details, err := g.evaluate(ctx, flagKey, user, defaultValue, func(v interface{}) (interface{}, bool) {
+		return v, true
+	})
+	return RawEvaluationDetails{Value: value, EvaluationDetails: details}, err
+}
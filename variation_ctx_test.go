@@ -0,0 +1,117 @@
+package ffclient
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thomaspoignant/go-feature-flag/ffuser"
+	"github.com/thomaspoignant/go-feature-flag/internal/exporter"
+	"github.com/thomaspoignant/go-feature-flag/internal/model"
+	"github.com/thomaspoignant/go-feature-flag/testutils/testconvert"
+)
+
+func TestVariationCtx(t *testing.T) {
+	gffClient := &GoFeatureFlag{
+		bgUpdater: newBackgroundUpdater(5),
+		cache: NewCacheMock(&model.FlagData{
+			Rule:       testconvert.String("key eq \"random-key\""),
+			Percentage: testconvert.Float64(100),
+			True:       testconvert.Interface("true"),
+			False:      testconvert.Interface("false"),
+			Default:    testconvert.Interface("default"),
+		}, nil),
+		config: Config{
+			PollingInterval: 0,
+			Logger:          log.New(ioutil.Discard, "", 0),
+		},
+	}
+	user := ffuser.NewUser("random-key")
+
+	t.Run("Evaluate with a context", func(t *testing.T) {
+		ctx := ffuser.NewContext(context.Background(), user)
+		ctxUser, _ := ffuser.FromContext(ctx)
+		got, err := gffClient.StringVariationCtx(ctx, "test-flag", ctxUser, "sdk-default")
+		assert.NoError(t, err)
+		assert.Equal(t, "true", got)
+	})
+
+	t.Run("Evaluate with a canceled context", func(t *testing.T) {
+		// the flags of the cache are evaluated even if the caller has canceled the context.
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		got, err := gffClient.StringVariationDetailsCtx(ctx, "test-flag", user, "sdk-default")
+		assert.NoError(t, err)
+		assert.Equal(t, StringEvaluationDetails{
+			Value: "true",
+			EvaluationDetails: EvaluationDetails{
+				Variation: "True",
+				Reason:    ReasonTargetingMatch,
+			},
+		}, got)
+	})
+
+	t.Run("Evaluate with the package level function", func(t *testing.T) {
+		ff = gffClient
+		defer func() { ff = nil }()
+		got, err := StringVariationCtx(context.Background(), "test-flag", user, "sdk-default")
+		assert.NoError(t, err)
+		assert.Equal(t, "true", got)
+	})
+	t.Run("Evaluate without conversion", func(t *testing.T) {
+		got, err := gffClient.RawVariationDetailsCtx(context.Background(), "test-flag", user, 12)
+		assert.NoError(t, err)
+		assert.Equal(t, RawEvaluationDetails{
+			Value: "true",
+			EvaluationDetails: EvaluationDetails{
+				Variation: "True",
+				Reason:    ReasonTargetingMatch,
+			},
+		}, got)
+
+	})
+}
+
+type ctxKey struct{}
+
+// ctxExporter keeps the value of ctxKey in the context of the last export.
+type ctxExporter struct {
+	value interface{}
+}
+
+func (e *ctxExporter) Export(ctx context.Context, _ *log.Logger, _ []exporter.FeatureEvent) error {
+	e.value = ctx.Value(ctxKey{})
+	return nil
+}
+
+func (e *ctxExporter) IsBulk() bool {
+	return false
+}
+
+func TestVariationCtx_exporter(t *testing.T) {
+	dataExporter := &ctxExporter{}
+	gffClient := &GoFeatureFlag{
+		bgUpdater: newBackgroundUpdater(5),
+		cache: NewCacheMock(&model.FlagData{
+			Percentage: testconvert.Float64(100),
+			True:       testconvert.Interface(true),
+			False:      testconvert.Interface(false),
+			Default:    testconvert.Interface(false),
+		}, nil),
+		config: Config{
+			PollingInterval: 0,
+			Logger:          log.New(ioutil.Discard, "", 0),
+		},
+		dataExporter: exporter.NewDataExporterScheduler(context.Background(), 0, 0, dataExporter, nil),
+	}
+	defer gffClient.dataExporter.Close()
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request-id")
+	got, err := gffClient.BoolVariationCtx(ctx, "test-flag", ffuser.NewUser("random-key"), false)
+	assert.NoError(t, err)
+	assert.True(t, got)
+	assert.Equal(t, "request-id", dataExporter.value)
+}
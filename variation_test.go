@@ -31,10 +31,22 @@ func NewCacheMock(flag model.Flag, err error) cache.Cache {
 func (c *cacheMock) UpdateCache(loadedFlags []byte, fileFormat string) error {
 	return nil
 }
+func (c *cacheMock) UpdateCacheFromFiles(files []cache.FlagFile) ([]cache.MergeConflict, error) {
+	return nil, nil
+}
 func (c *cacheMock) Close() {}
 func (c *cacheMock) GetFlag(key string) (model.Flag, error) {
 	return c.flag, c.err
 }
+func (c *cacheMock) AllFlags() (cache.FlagsCache, error) {
+	return nil, c.err
+}
+func (c *cacheMock) Snapshot() (cache.Snapshot, error) {
+	return cache.Snapshot{}, c.err
+}
+func (c *cacheMock) GetSegment(name string) (model.Segment, bool) {
+	return model.Segment{}, false
+}
 
 func TestBoolVariation(t *testing.T) {
 	type args struct {
@@ -1099,3 +1111,233 @@ func TestIntVariation(t *testing.T) {
 		})
 	}
 }
+
+func TestBoolVariationDetails(t *testing.T) {
+	type args struct {
+		flagKey      string
+		user         ffuser.User
+		defaultValue bool
+		cacheMock    cache.Cache
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    BoolEvaluationDetails
+		wantErr bool
+	}{
+		{
+			name: "Flag not found",
+			args: args{
+				flagKey:      "key-not-exist",
+				user:         ffuser.NewUser("random-key"),
+				defaultValue: true,
+				cacheMock:    NewCacheMock(&model.FlagData{}, errors.New("flag [key-not-exist] does not exists")),
+			},
+			want: BoolEvaluationDetails{
+				Value: true,
+				EvaluationDetails: EvaluationDetails{
+					Variation: "SdkDefault",
+					Reason:    ReasonFlagNotFound,
+					ErrorCode: ErrorCodeFlagNotFound,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Flag disabled",
+			args: args{
+				flagKey:      "disable-flag",
+				user:         ffuser.NewUser("random-key"),
+				defaultValue: true,
+				cacheMock: NewCacheMock(&model.FlagData{
+					Disable: testconvert.Bool(true),
+				}, nil),
+			},
+			want: BoolEvaluationDetails{
+				Value: true,
+				EvaluationDetails: EvaluationDetails{
+					Variation: "SdkDefault",
+					Reason:    ReasonDisabled,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Type mismatch",
+			args: args{
+				flagKey:      "test-flag",
+				user:         ffuser.NewUser("random-key"),
+				defaultValue: false,
+				cacheMock: NewCacheMock(&model.FlagData{
+					Percentage: testconvert.Float64(100),
+					Default:    testconvert.Interface("default"),
+					True:       testconvert.Interface("true"),
+					False:      testconvert.Interface("false"),
+				}, nil),
+			},
+			want: BoolEvaluationDetails{
+				Value: false,
+				EvaluationDetails: EvaluationDetails{
+					Variation: "SdkDefault",
+					Reason:    ReasonTypeMismatch,
+					ErrorCode: ErrorCodeTypeMismatch,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Rule not apply",
+			args: args{
+				flagKey:      "test-flag",
+				user:         ffuser.NewUser("random-key"),
+				defaultValue: false,
+				cacheMock: NewCacheMock(&model.FlagData{
+					Rule:       testconvert.String("key eq \"key\""),
+					Percentage: testconvert.Float64(100),
+					Default:    testconvert.Interface(true),
+					True:       testconvert.Interface(false),
+					False:      testconvert.Interface(false),
+				}, nil),
+			},
+			want: BoolEvaluationDetails{
+				Value: true,
+				EvaluationDetails: EvaluationDetails{
+					Variation: "Default",
+					Reason:    ReasonDefault,
+				},
+			},
+		},
+		{
+			name: "Rule apply",
+			args: args{
+				flagKey:      "test-flag",
+				user:         ffuser.NewUser("random-key"),
+				defaultValue: false,
+				cacheMock: NewCacheMock(&model.FlagData{
+					Rule:       testconvert.String("key eq \"random-key\""),
+					Percentage: testconvert.Float64(100),
+					Default:    testconvert.Interface(false),
+					True:       testconvert.Interface(true),
+					False:      testconvert.Interface(false),
+				}, nil),
+			},
+			want: BoolEvaluationDetails{
+				Value: true,
+				EvaluationDetails: EvaluationDetails{
+					Variation: "True",
+					Reason:    ReasonTargetingMatch,
+				},
+			},
+		},
+		{
+			name: "Targeting rule apply",
+			args: args{
+				flagKey:      "test-flag",
+				user:         ffuser.NewUser("random-key"),
+				defaultValue: false,
+				cacheMock: NewCacheMock(&model.FlagData{
+					Targeting: []model.TargetingRule{
+						{
+							Name:      testconvert.String("beta-users"),
+							Query:     testconvert.String("key eq \"random-key\""),
+							Variation: testconvert.String("True"),
+						},
+					},
+					Default: testconvert.Interface(false),
+					True:    testconvert.Interface(true),
+					False:   testconvert.Interface(false),
+				}, nil),
+			},
+			want: BoolEvaluationDetails{
+				Value: true,
+				EvaluationDetails: EvaluationDetails{
+					Variation: "True",
+					Reason:    ReasonTargetingMatch,
+					RuleName:  "beta-users",
+				},
+			},
+		},
+		{
+			name: "Targeted user",
+			args: args{
+				flagKey:      "test-flag",
+				user:         ffuser.NewUser("customer-42"),
+				defaultValue: true,
+				cacheMock: NewCacheMock(&model.FlagData{
+					Targets:    map[string]string{"customer-42": "False"},
+					Percentage: testconvert.Float64(100),
+					Default:    testconvert.Interface(false),
+					True:       testconvert.Interface(true),
+					False:      testconvert.Interface(false),
+				}, nil),
+			},
+			want: BoolEvaluationDetails{
+				Value: false,
+				EvaluationDetails: EvaluationDetails{
+					Variation: "False",
+					Reason:    ReasonTargetingKey,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gffClient := &GoFeatureFlag{
+				bgUpdater: newBackgroundUpdater(5),
+				cache:     tt.args.cacheMock,
+				config: Config{
+					PollingInterval: 0,
+					Logger:          log.New(ioutil.Discard, "", 0),
+				},
+			}
+
+			got, err := gffClient.BoolVariationDetails(tt.args.flagKey, tt.args.user, tt.args.defaultValue)
+			assert.Equal(t, tt.wantErr, err != nil, "BoolVariationDetails() error = %v, wantErr %v", err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestStringVariationDetails_namedVariations(t *testing.T) {
+	gffClient := &GoFeatureFlag{
+		bgUpdater: newBackgroundUpdater(5),
+		cache: NewCacheMock(&model.FlagData{
+			Variations: map[string]interface{}{
+				"blue":  "#0000FF",
+				"green": "#00FF00",
+			},
+			Targeting: []model.TargetingRule{
+				{
+					Query:     testconvert.String("key eq \"random-key\""),
+					Variation: testconvert.String("green"),
+				},
+			},
+			Default: testconvert.Interface("#FFFFFF"),
+		}, nil),
+		config: Config{
+			PollingInterval: 0,
+			Logger:          log.New(ioutil.Discard, "", 0),
+		},
+	}
+
+	got, err := gffClient.StringVariationDetails("color", ffuser.NewUser("random-key"), "#000000")
+	assert.NoError(t, err)
+	assert.Equal(t, StringEvaluationDetails{
+		Value: "#00FF00",
+		EvaluationDetails: EvaluationDetails{
+			Variation: "green",
+			Reason:    ReasonTargetingMatch,
+			RuleName:  "targeting[0]",
+		},
+	}, got)
+
+	got, err = gffClient.StringVariationDetails("color", ffuser.NewUser("other-key"), "#000000")
+	assert.NoError(t, err)
+	assert.Equal(t, StringEvaluationDetails{
+		Value: "#FFFFFF",
+		EvaluationDetails: EvaluationDetails{
+			Variation: "Default",
+			Reason:    ReasonStatic,
+		},
+	}, got)
+}